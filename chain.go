@@ -1,7 +1,11 @@
 package cron
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"runtime"
 	"sync"
 	"time"
@@ -10,6 +14,8 @@ import (
 )
 
 // JobWrapper 用某些行为装饰给定的Job。
+// 包装器应使用 RunWithContext 调用被包装的作业，以传递每次运行的上下文
+// 并观察作业返回的错误。
 type JobWrapper func(Job) Job
 
 // Chain 是JobWrapper的序列，用横切行为（如日志记录或同步）
@@ -39,23 +45,48 @@ func (c Chain) Then(j Job) Job {
 	return j
 }
 
+// ThenCtx 将 JobContext 适配为 ContextJob，并用链中的所有 JobWrapper 装饰它。
+func (c Chain) ThenCtx(j JobContext) Job {
+	return c.Then(ContextJob{j})
+}
+
+// PanicError 是 Recover 从作业中恢复的panic，作为错误返回给外层包装器。
+type PanicError struct {
+	// Value 是传递给panic的值。
+	Value interface{}
+	// Stack 是panic发生时的goroutine堆栈。
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap 在panic值是错误时返回它。
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
 // Recover 恢复包装作业中的panic并使用提供的记录器记录它们。
+// 恢复的panic以 *PanicError 的形式返回，使外层包装器可以观察到它。
 func Recover(logger Logger) JobWrapper {
 	return func(j Job) Job {
-		return FuncJob(func() {
+		return FuncErrJob(func(ctx context.Context) (err error) {
 			defer func() {
 				if r := recover(); r != nil {
 					const size = 64 << 10
 					buf := make([]byte, size)
 					buf = buf[:runtime.Stack(buf, false)]
-					err, ok := r.(error)
+					logErr, ok := r.(error)
 					if !ok {
-						err = fmt.Errorf("%v", r)
+						logErr = fmt.Errorf("%v", r)
 					}
-					logger.Error(err, "panic", "stack", "...\n"+string(buf))
+					logger.Error(logErr, "panic", "stack", "...\n"+string(buf))
+					err = &PanicError{Value: r, Stack: buf}
 				}
 			}()
-			j.Run()
+			return RunWithContext(ctx, j)
 		})
 	}
 }
@@ -65,32 +96,241 @@ func Recover(logger Logger) JobWrapper {
 func DelayIfStillRunning(logger Logger) JobWrapper {
 	return func(j Job) Job {
 		var mu sync.Mutex
-		return FuncJob(func() {
+		return FuncErrJob(func(ctx context.Context) error {
 			start := time2.Now()
 			mu.Lock()
 			defer mu.Unlock()
 			if dur := time.Since(start); dur > time.Minute {
 				logger.Info("delay", "duration", dur)
 			}
-			j.Run()
+			return RunWithContext(ctx, j)
 		})
 	}
 }
 
+// ErrSkipped 由跳过运行的包装器（例如 SkipIfStillRunning）返回，
+// 使外层包装器和监听器可以区分被跳过的运行和失败的运行。Cron 不会将它记录为错误。
+var ErrSkipped = errors.New("job skipped")
+
 // SkipIfStillRunning 如果前一个调用仍在运行，则跳过Job的调用。
-// 它在Info级别向给定记录器记录跳过。
+// 它在Info级别向给定记录器记录跳过，并返回 ErrSkipped。
 func SkipIfStillRunning(logger Logger) JobWrapper {
+	return LimitConcurrency(1, logger)
+}
+
+// LimitConcurrency 如果已经有 n 个调用正在运行，则跳过Job的调用。
+// 它将 SkipIfStillRunning 推广到大于 1 的并发上限，
+// 在Info级别向给定记录器记录跳过，并返回 ErrSkipped。如果 n 不是正数，它会恐慌。
+func LimitConcurrency(n int, logger Logger) JobWrapper {
+	if n <= 0 {
+		panic("cron: concurrency limit must be positive")
+	}
 	return func(j Job) Job {
-		var ch = make(chan struct{}, 1)
-		ch <- struct{}{}
-		return FuncJob(func() {
+		var ch = make(chan struct{}, n)
+		for i := 0; i < n; i++ {
+			ch <- struct{}{}
+		}
+		return FuncErrJob(func(ctx context.Context) error {
 			select {
 			case v := <-ch:
 				defer func() { ch <- v }()
-				j.Run()
+				return RunWithContext(ctx, j)
 			default:
 				logger.Info("skip")
+				return ErrSkipped
+			}
+		})
+	}
+}
+
+// ErrTimeout 由 Timeout 和 TimeoutAndAbandon 返回，表示作业的运行超过了允许的时长。
+var ErrTimeout = errors.New("job timed out")
+
+// Timeout 为每次运行提供一个在 d 之后到期的上下文。
+// 如果运行超时，它在Error级别向给定记录器记录超时，并返回 ErrTimeout，
+// 除非作业返回了与超时无关的错误。Cron 不会再次记录 ErrTimeout。
+//
+// 作业必须观察上下文才能被及时取消：Timeout 会一直等待作业返回。
+// 对于不观察上下文的作业，请使用 TimeoutAndAbandon。
+func Timeout(d time.Duration, logger Logger) JobWrapper {
+	return func(j Job) Job {
+		return FuncErrJob(func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			err := RunWithContext(ctx, j)
+			if ctx.Err() != context.DeadlineExceeded {
+				return err
 			}
+			logger.Error(ErrTimeout, "timeout", "timeout", d)
+			if err == nil || errors.Is(err, context.DeadlineExceeded) {
+				return ErrTimeout
+			}
+			return err
 		})
 	}
 }
+
+// TimeoutAndAbandon 类似于 Timeout，但在超时后不再等待作业：
+// 它立即返回 ErrTimeout，让作业在自己的goroutine中继续运行直到结束。
+// 如果上下文因其他原因（例如 Cron 停止）提前结束，作业同样被放弃，并返回上下文的错误。
+// 被放弃的作业不再计入 Stop 等待的运行，它的panic会被恢复并记录。
+// 放弃作业时，它在Error级别向给定记录器记录。
+//
+// 被放弃的goroutine会一直占用资源，因此只应将它用于无法观察上下文的作业。
+func TimeoutAndAbandon(d time.Duration, logger Logger) JobWrapper {
+	return func(j Job) Job {
+		return FuncErrJob(func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			type result struct {
+				err      error
+				panicked bool
+				value    interface{}
+			}
+			var (
+				mu        sync.Mutex
+				abandoned bool
+				done      = make(chan result, 1)
+			)
+			go func() {
+				var res result
+				defer func() {
+					if r := recover(); r != nil {
+						res.panicked, res.value = true, r
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					if abandoned && res.panicked {
+						logger.Error(fmt.Errorf("%v", res.value), "panic in abandoned job")
+					}
+					done <- res
+				}()
+				res.err = RunWithContext(ctx, j)
+			}()
+
+			var res result
+			select {
+			case res = <-done:
+			case <-ctx.Done():
+				mu.Lock()
+				select {
+				// 作业可能恰好在上下文结束时完成。
+				case res = <-done:
+				default:
+					abandoned = true
+				}
+				mu.Unlock()
+			}
+			if !abandoned {
+				if res.panicked {
+					panic(res.value)
+				}
+				return res.err
+			}
+
+			err := ctx.Err()
+			if err == context.DeadlineExceeded {
+				err = ErrTimeout
+			}
+			logger.Error(err, "abandon", "timeout", d)
+			return err
+		})
+	}
+}
+
+// RetryPolicy 描述 Retry 包装器如何重试失败的运行。
+type RetryPolicy struct {
+	// MaxAttempts 是同一次激活中运行作业的最大次数，包括第一次运行。
+	// 小于 1 的值被视为 1，即不重试。
+	MaxAttempts int
+
+	// InitialBackoff 是第一次重试之前的等待时间。
+	InitialBackoff time.Duration
+
+	// MaxBackoff 是两次运行之间等待时间的上限。零值表示没有上限。
+	MaxBackoff time.Duration
+
+	// Multiplier 是每次重试后等待时间的增长倍数。小于 1 的值被视为 2。
+	Multiplier float64
+
+	// Jitter 是随机调整等待时间的比例，取值在 0 到 1 之间，超出范围的值被截断。
+	// 例如 0.2 表示实际等待时间在计算值的 80% 到 120% 之间均匀分布。
+	Jitter float64
+}
+
+// backoff 返回第 attempt 次运行失败之后的等待时间，attempt 从 1 开始。
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if jitter := math.Min(p.Jitter, 1); jitter > 0 {
+		d *= 1 + jitter*(2*rand.Float64()-1)
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	// 没有上限时，等待时间在多次重试之后可能超出 time.Duration 的范围。
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
+// Retry 在作业返回错误或发生panic时，在同一次激活中按照给定的策略重试它，
+// 在每次重试之间按指数增长的时间等待。panic 被恢复并转换为 *PanicError。
+// 除最后一次以外的失败运行在Error级别向给定记录器记录；最后一次运行的错误被返回，
+// 由调用者（例如 Cron）记录，因此只在Info级别记录，除非它是 panic。
+//
+// 被跳过的运行（ErrSkipped）不会被重试。如果运行的上下文在等待期间结束，
+// Retry 停止重试并返回最后一次运行的错误。所有运行都失败时，返回最后一次运行的错误。
+func Retry(policy RetryPolicy, logger Logger) JobWrapper {
+	return func(j Job) Job {
+		return FuncErrJob(func(ctx context.Context) error {
+			maxAttempts := policy.MaxAttempts
+			if maxAttempts < 1 {
+				maxAttempts = 1
+			}
+			for attempt := 1; ; attempt++ {
+				err := runRecovered(ctx, j)
+				if err == nil || errors.Is(err, ErrSkipped) {
+					return err
+				}
+				var panicErr *PanicError
+				if attempt >= maxAttempts && !errors.As(err, &panicErr) {
+					logger.Info("attempt failed", "attempt", attempt, "max", maxAttempts, "error", err)
+				} else {
+					logger.Error(err, "attempt failed", "attempt", attempt, "max", maxAttempts)
+				}
+				if attempt >= maxAttempts {
+					return err
+				}
+
+				backoff := policy.backoff(attempt)
+				logger.Info("retry", "attempt", attempt+1, "backoff", backoff)
+				timer := time.NewTimer(backoff)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return err
+				}
+			}
+		})
+	}
+}
+
+// runRecovered 运行作业，并将其中的panic转换为 *PanicError。
+func runRecovered(ctx context.Context, j Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			const size = 64 << 10
+			buf := make([]byte, size)
+			buf = buf[:runtime.Stack(buf, false)]
+			err = &PanicError{Value: r, Stack: buf}
+		}
+	}()
+	return RunWithContext(ctx, j)
+}
@@ -1,9 +1,14 @@
 package cron
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"math"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -239,3 +244,306 @@ func TestChainSkipIfStillRunning(t *testing.T) {
 	})
 
 }
+
+type ctxKey struct{}
+
+func TestChainPropagatesContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	wrappers := map[string]JobWrapper{
+		"Recover":             Recover(DiscardLogger),
+		"DelayIfStillRunning": DelayIfStillRunning(DiscardLogger),
+		"SkipIfStillRunning":  SkipIfStillRunning(DiscardLogger),
+	}
+	for name, wrapper := range wrappers {
+		t.Run(name, func(t *testing.T) {
+			var got interface{}
+			job := NewChain(wrapper).ThenCtx(FuncJobCtx(func(ctx context.Context) {
+				got = ctx.Value(ctxKey{})
+			}))
+			RunWithContext(ctx, job)
+			if got != "value" {
+				t.Errorf("expected context to reach the job, got %v", got)
+			}
+		})
+	}
+
+	t.Run("Run uses a background context", func(t *testing.T) {
+		var got context.Context
+		NewChain(Recover(DiscardLogger)).ThenCtx(FuncJobCtx(func(ctx context.Context) {
+			got = ctx
+		})).Run()
+		if got == nil || got.Err() != nil {
+			t.Errorf("expected a live background context, got %v", got)
+		}
+	})
+}
+
+func TestChainPropagatesError(t *testing.T) {
+	expected := errors.New("job failed")
+	failingJob := FuncErrJob(func(context.Context) error { return expected })
+
+	var observed error
+	observer := func(j Job) Job {
+		return FuncErrJob(func(ctx context.Context) error {
+			observed = RunWithContext(ctx, j)
+			return observed
+		})
+	}
+
+	err := RunWithContext(context.Background(), NewChain(
+		observer,
+		Recover(DiscardLogger),
+		DelayIfStillRunning(DiscardLogger),
+		SkipIfStillRunning(DiscardLogger),
+	).Then(failingJob))
+	if err != expected {
+		t.Errorf("expected %v, got %v", expected, err)
+	}
+	if observed != expected {
+		t.Errorf("expected wrapper to observe %v, got %v", expected, observed)
+	}
+}
+
+func TestChainRecoverReturnsPanicError(t *testing.T) {
+	cause := errors.New("cause")
+	err := RunWithContext(context.Background(),
+		NewChain(Recover(DiscardLogger)).Then(FuncJob(func() { panic(cause) })))
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected a *PanicError, got %v", err)
+	}
+	if panicErr.Value != cause || len(panicErr.Stack) == 0 {
+		t.Errorf("unexpected panic error: %#v", panicErr)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected panic error to unwrap to the panic value")
+	}
+}
+
+func TestChainSkipReturnsErrSkipped(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	job := NewChain(SkipIfStillRunning(DiscardLogger)).Then(FuncJob(func() {
+		close(started)
+		<-release
+	}))
+	go job.Run()
+	<-started
+
+	if err := RunWithContext(context.Background(), job); err != ErrSkipped {
+		t.Errorf("expected ErrSkipped, got %v", err)
+	}
+	close(release)
+}
+
+func TestChainLimitConcurrency(t *testing.T) {
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	started.Add(2)
+	job := NewChain(LimitConcurrency(2, DiscardLogger)).Then(FuncJob(func() {
+		started.Done()
+		<-release
+	}))
+	go job.Run()
+	go job.Run()
+	started.Wait()
+
+	if err := RunWithContext(context.Background(), job); err != ErrSkipped {
+		t.Errorf("expected ErrSkipped, got %v", err)
+	}
+	close(release)
+}
+
+func TestChainTimeout(t *testing.T) {
+	var buf syncWriter
+	job := NewChain(Timeout(10*time.Millisecond, newBufLogger(&buf))).Then(FuncErrJob(
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+
+	if err := RunWithContext(context.Background(), job); err != ErrTimeout {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "timeout") {
+		t.Errorf("expected the timeout to be logged, got %q", buf.String())
+	}
+}
+
+func TestChainTimeoutNotExceeded(t *testing.T) {
+	failure := errors.New("failure")
+	job := NewChain(Timeout(time.Minute, DiscardLogger)).Then(FuncErrJob(
+		func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("expected the job context to have a deadline")
+			}
+			return failure
+		}))
+
+	if err := RunWithContext(context.Background(), job); err != failure {
+		t.Errorf("expected the job's error, got %v", err)
+	}
+}
+
+func TestChainTimeoutAndAbandon(t *testing.T) {
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	job := NewChain(TimeoutAndAbandon(10*time.Millisecond, DiscardLogger)).Then(FuncJob(func() {
+		<-release
+		close(finished)
+	}))
+
+	if err := RunWithContext(context.Background(), job); err != ErrTimeout {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+	close(release)
+	select {
+	case <-finished:
+	case <-time.After(OneSecond):
+		t.Error("expected the abandoned job to keep running")
+	}
+}
+
+func TestChainTimeoutAndAbandonPanic(t *testing.T) {
+	job := NewChain(Recover(DiscardLogger), TimeoutAndAbandon(time.Minute, DiscardLogger)).
+		Then(FuncJob(func() { panic("job") }))
+
+	var panicErr *PanicError
+	if err := RunWithContext(context.Background(), job); !errors.As(err, &panicErr) || panicErr.Value != "job" {
+		t.Errorf("expected the panic to propagate, got %v", err)
+	}
+}
+
+func TestChainRetry(t *testing.T) {
+	failure := errors.New("failure")
+	var calls int
+	job := NewChain(Retry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, DiscardLogger)).
+		Then(FuncErrJob(func(context.Context) error {
+			calls++
+			switch calls {
+			case 1:
+				return failure
+			case 2:
+				panic("flaky")
+			}
+			return nil
+		}))
+
+	if err := RunWithContext(context.Background(), job); err != nil {
+		t.Errorf("expected the third attempt to succeed, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestChainRetryExhausted(t *testing.T) {
+	var calls int
+	job := NewChain(Recover(DiscardLogger), Retry(RetryPolicy{MaxAttempts: 2}, DiscardLogger)).
+		Then(FuncJob(func() {
+			calls++
+			panic(calls)
+		}))
+
+	var panicErr *PanicError
+	if err := RunWithContext(context.Background(), job); !errors.As(err, &panicErr) || panicErr.Value != 2 {
+		t.Errorf("expected the last attempt's panic, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+}
+
+// 测试最后一次失败的运行不在Error级别记录，因为它的错误由调用者记录。
+func TestChainRetryLogsLastAttemptOnce(t *testing.T) {
+	var buf syncWriter
+	job := NewChain(Retry(RetryPolicy{MaxAttempts: 2}, newBufLogger(&buf))).
+		Then(FuncErrJob(func(context.Context) error {
+			return errors.New("failure")
+		}))
+
+	RunWithContext(context.Background(), job)
+	if out := buf.String(); strings.Count(out, "attempt failed") != 1 || !strings.Contains(out, "attempt=1") {
+		t.Errorf("expected only the first attempt to be logged as an error, got %q", out)
+	}
+}
+
+func TestChainRetryStopsWhenContextDone(t *testing.T) {
+	failure := errors.New("failure")
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	job := NewChain(Retry(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}, DiscardLogger)).
+		Then(FuncErrJob(func(context.Context) error {
+			calls++
+			cancel()
+			return failure
+		}))
+
+	if err := RunWithContext(ctx, job); err != failure {
+		t.Errorf("expected the last error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls)
+	}
+}
+
+func TestChainRetryDoesNotRetrySkipped(t *testing.T) {
+	var calls int
+	job := NewChain(Retry(RetryPolicy{MaxAttempts: 3}, DiscardLogger)).
+		Then(FuncErrJob(func(context.Context) error {
+			calls++
+			return ErrSkipped
+		}))
+
+	if err := RunWithContext(context.Background(), job); err != ErrSkipped || calls != 1 {
+		t.Errorf("expected a single skipped attempt, got %v after %d attempts", err, calls)
+	}
+
+	// 被其他包装器包装的跳过同样不会被重试。
+	calls = 0
+	job = NewChain(Retry(RetryPolicy{MaxAttempts: 3}, DiscardLogger)).
+		Then(FuncErrJob(func(context.Context) error {
+			calls++
+			return fmt.Errorf("lock held: %w", ErrSkipped)
+		}))
+	if err := RunWithContext(context.Background(), job); !errors.Is(err, ErrSkipped) || calls != 1 {
+		t.Errorf("expected a single wrapped skipped attempt, got %v after %d attempts", err, calls)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := policy.backoff(i + 1); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("expected jittered backoff within 50%%, got %v", got)
+		}
+		if got := policy.backoff(4); got < 2500*time.Millisecond || got > policy.MaxBackoff {
+			t.Fatalf("expected jittered backoff at the cap within [2.5s, %v], got %v", policy.MaxBackoff, got)
+		}
+	}
+
+	// 没有上限时，很大的重试次数不会溢出为负数。
+	uncapped := RetryPolicy{InitialBackoff: time.Second, Multiplier: 2}
+	for _, attempt := range []int{35, 64, 2000} {
+		if got := uncapped.backoff(attempt); got != math.MaxInt64 {
+			t.Errorf("attempt %d: expected the maximum duration, got %v", attempt, got)
+		}
+	}
+
+	// 大于 1 的 Jitter 被截断为 1，等待时间不会为负数。
+	uncapped.Jitter = 1.5
+	for i := 0; i < 1000; i++ {
+		if got := uncapped.backoff(1); got < 0 || got > 2*time.Second {
+			t.Fatalf("expected jitter to be clamped to 1, got %v", got)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package cron
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-utils2/time2"
+)
+
+// Clock 是 Cron 获取当前时间和创建定时器的来源。
+// 默认使用系统时钟；测试中可以通过 WithClock 安装 FakeClock，
+// 从而在不真实等待的情况下驱动调度循环。
+type Clock interface {
+	// Now 返回当前时间。
+	Now() time.Time
+	// NewTimer 创建一个在 d 之后触发的定时器。
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer 是 Clock 创建的定时器，对应 *time.Timer 的子集。
+type Timer interface {
+	// C 返回定时器触发时接收当前时间的通道。
+	C() <-chan time.Time
+	// Stop 阻止定时器触发。如果调用停止了定时器则返回 true，
+	// 如果定时器已经触发或已被停止则返回 false。
+	Stop() bool
+}
+
+// realClock 是基于系统时间的 Clock。
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time2.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (rt realTimer) C() <-chan time.Time { return rt.t.C }
+func (rt realTimer) Stop() bool          { return rt.t.Stop() }
+
+// FakeClock 是一个手动推进的 Clock，用于确定性地测试调度行为。
+// 时间只在调用 Advance 或 Set 时前进，此时所有到期的定时器都会触发。
+//
+// 由于调度循环在自己的 goroutine 中创建定时器，测试通常应在推进时间之前
+// 调用 BlockUntil，以确保循环已经进入等待状态：
+//
+//	clock := cron.NewFakeClock(start)
+//	c := cron.New(cron.WithClock(clock))
+//	c.AddFunc("@hourly", job)
+//	c.Start()
+//	clock.BlockUntil(1)
+//	clock.Advance(time.Hour)
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	timers  []*fakeTimer
+	nextSeq uint64
+}
+
+// NewFakeClock 返回一个当前时间为 now 的 FakeClock。
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now 返回假时钟的当前时间。
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer 创建一个在假时钟前进 d 之后触发的定时器。
+// 非正的 d 会使定时器立即触发。
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextSeq++
+	t := &fakeTimer{
+		clock:    c,
+		ch:       make(chan time.Time, 1),
+		deadline: c.now.Add(d),
+		seq:      c.nextSeq,
+	}
+	if d <= 0 {
+		t.ch <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance 将假时钟向前推进 d，并触发所有到期的定时器。
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(c.now.Add(d))
+}
+
+// Set 将假时钟设置为 t，并触发所有到期的定时器。
+// 将时钟设置到过去不会触发任何定时器。
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(t)
+}
+
+// BlockUntil 阻塞直到至少有 n 个尚未触发且未停止的定时器。
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *FakeClock) setLocked(t time.Time) {
+	c.now = t
+
+	// 按到期时间（然后按创建顺序）触发定时器，使结果是确定性的。
+	sort.Slice(c.timers, func(i, j int) bool {
+		if c.timers[i].deadline.Equal(c.timers[j].deadline) {
+			return c.timers[i].seq < c.timers[j].seq
+		}
+		return c.timers[i].deadline.Before(c.timers[j].deadline)
+	})
+	var pending []*fakeTimer
+	for _, timer := range c.timers {
+		if timer.deadline.After(t) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.ch <- t
+	}
+	c.timers = pending
+	c.cond.Broadcast()
+}
+
+// removeLocked 从等待列表中删除定时器，如果它仍在等待则返回 true。
+func (c *FakeClock) removeLocked(t *fakeTimer) bool {
+	for i, timer := range c.timers {
+		if timer == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	ch       chan time.Time
+	deadline time.Time
+	seq      uint64
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.removeLocked(t)
+}
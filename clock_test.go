@@ -0,0 +1,111 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClockTimer(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	timer := clock.NewTimer(time.Minute)
+	clock.Advance(59 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("expected timer not to fire before its deadline")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case now := <-timer.C():
+		if !now.Equal(start.Add(time.Minute)) {
+			t.Errorf("expected %v, got %v", start.Add(time.Minute), now)
+		}
+	default:
+		t.Fatal("expected timer to fire at its deadline")
+	}
+
+	if timer.Stop() {
+		t.Error("expected Stop on a fired timer to return false")
+	}
+}
+
+func TestFakeClockStop(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	timer := clock.NewTimer(time.Second)
+	if !timer.Stop() {
+		t.Error("expected Stop on a pending timer to return true")
+	}
+	clock.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Error("expected stopped timer not to fire")
+	default:
+	}
+}
+
+func TestFakeClockSet(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	timer := clock.NewTimer(time.Hour)
+	target := start.Add(24 * time.Hour)
+	clock.Set(target)
+	if now := clock.Now(); !now.Equal(target) {
+		t.Errorf("expected %v, got %v", target, now)
+	}
+	select {
+	case now := <-timer.C():
+		if !now.Equal(target) {
+			t.Errorf("expected timer to receive %v, got %v", target, now)
+		}
+	default:
+		t.Fatal("expected timer to fire")
+	}
+}
+
+// 使用假时钟以毫秒级时间测试小时和月级别的调度。
+func TestCronWithFakeClock(t *testing.T) {
+	start := time.Date(2026, 1, 31, 23, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	hourly := make(chan time.Time, 10)
+	monthly := make(chan time.Time, 10)
+	cron.AddFunc("@hourly", func() { hourly <- clock.Now() })
+	cron.AddFunc("@monthly", func() { monthly <- clock.Now() })
+	cron.Start()
+	defer cron.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+
+	for _, ch := range []chan time.Time{hourly, monthly} {
+		select {
+		case now := <-ch:
+			if expected := start.Add(30 * time.Minute); !now.Equal(expected) {
+				t.Errorf("expected job run at %v, got %v", expected, now)
+			}
+		case <-time.After(OneSecond):
+			t.Fatal("expected job runs")
+		}
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	select {
+	case <-hourly:
+	case <-time.After(OneSecond):
+		t.Fatal("expected hourly job runs")
+	}
+	select {
+	case <-monthly:
+		t.Error("expected monthly job does not run again")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	entry := cron.Entries()[0]
+	if expected := time.Date(2026, 2, 1, 2, 0, 0, 0, time.UTC); !entry.Next.Equal(expected) {
+		t.Errorf("expected next run at %v, got %v", expected, entry.Next)
+	}
+}
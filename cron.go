@@ -1,32 +1,64 @@
 package cron
 
 import (
+	"container/heap"
 	"context"
+	"errors"
+	"fmt"
 	"sort"
 	"sync"
 	"time"
-
-	"github.com/go-utils2/time2"
 )
 
 // Cron 跟踪任意数量的条目，按照计划调用相关的函数。
 // 它可以被启动、停止，并且可以在运行时检查条目。
 type Cron struct {
-	entries   []*Entry
-	chain     Chain
-	stop      chan struct{}
-	add       chan *Entry
-	remove    chan EntryID
-	snapshot  chan chan []Entry
-	running   bool
-	logger    Logger
-	runningMu sync.Mutex
-	location  *time.Location
-	parser    ScheduleParser
-	nextID    EntryID
-	jobWaiter sync.WaitGroup
+	entries  entryHeap
+	index    map[EntryID]*Entry
+	chain    Chain
+	stop     chan struct{}
+	add      chan *Entry
+	remove   chan entryRemoval
+	snapshot chan chan []Entry
+	update   chan entryUpdate
+	lookup   chan entryLookup
+
+	running       bool
+	logger        Logger
+	runningMu     sync.Mutex
+	location      *time.Location
+	parser        ScheduleParser
+	clock         Clock
+	nextID        EntryID
+	jobWaiter     waitGroup
+	jobCtx        context.Context
+	jobCancel     context.CancelFunc
+	jobTimeout    time.Duration
+	misfirePolicy MisfirePolicy
+	store         JobStore
+	listeners     []Listener
+	pool          *workerPool
+	locker        Locker
+	lockTTL       time.Duration
+	elector       LeaderElector
+	leader        int32
+	elected       chan struct{}
+	names         map[string]EntryID
+	namesMu       sync.Mutex
+	jobs          map[EntryID]int
+	jobsMu        sync.Mutex
+	saves         map[string]EntryState
+	savesMu       sync.Mutex
+	saveWake      chan struct{}
+	flushMu       sync.Mutex
 }
 
+// ErrDuplicateName 在添加的条目名称已被同一 Cron 中的其他条目使用时返回。
+var ErrDuplicateName = errors.New("duplicate entry name")
+
+// ErrEntryNotFound 在按 ID 修改的条目不存在时返回。
+var ErrEntryNotFound = errors.New("entry not found")
+
 // ScheduleParser 是用于解析计划规范并返回 Schedule 的接口
 type ScheduleParser interface {
 	Parse(spec string) (Schedule, error)
@@ -37,6 +69,12 @@ type Job interface {
 	Run()
 }
 
+// JobContext 是可感知上下文的 cron 作业的接口。
+// 每次运行都会收到一个新的上下文，它在 Cron 停止或达到运行截止时间时被取消。
+type JobContext interface {
+	Run(ctx context.Context)
+}
+
 // Schedule 描述作业的执行周期。
 type Schedule interface {
 	// Next 返回下一个激活时间，晚于给定时间。
@@ -52,6 +90,10 @@ type Entry struct {
 	// ID 是此条目的 cron 分配的 ID，可用于查找快照或删除它。
 	ID EntryID
 
+	// Name 是此条目的稳定名称（可选），由 WithName 或 AddNamed* 设置。
+	// 与 ID 不同，它在进程重启后保持不变，在 Cron 实例中唯一，并用作 JobStore 的键。
+	Name string
+
 	// Schedule 是此作业应该运行的计划。
 	Schedule Schedule
 
@@ -67,28 +109,136 @@ type Entry struct {
 	// Job 是提交给 cron 的东西。
 	// 保留它是为了让需要稍后获取作业的用户代码（例如通过 Entries()）可以这样做。
 	Job Job
+
+	// MisfirePolicy 决定当此条目错过激活时间时如何处理。
+	MisfirePolicy MisfirePolicy
+
+	// StartAt 和 EndAt 限制条目的生命周期：早于 StartAt 或晚于 EndAt 的激活
+	// 不会发生。零值表示没有限制。
+	StartAt, EndAt time.Time
+
+	// MaxRuns 是条目按计划运行的最大次数，达到后条目被删除。零值表示不限制。
+	MaxRuns int
+
+	// RunCount 是条目按计划运行的次数，不包括通过 RunNow 手动触发的运行。
+	RunCount int
+
+	// MaxConcurrent 是此条目同时运行的最大次数，超出的运行被跳过。零值表示不限制。
+	MaxConcurrent int
+
+	// Paused 表示条目已被 Pause 暂停。暂停的条目保留在 Cron 中，
+	// 但在恢复之前不会运行，并且它的 Next 为零时间。
+	Paused bool
+
+	// labels 保存由 WithTags 和 WithMetadata 设置的标签和元数据。
+	// 它们放在指针后面，使 Entry 保持可比较；通过 Tags 和 Metadata 读取。
+	labels *entryLabels
+
+	// index 是条目在 Cron 的堆中的位置。
+	index int
+}
+
+// entryLabels 是条目的标签和元数据。它在条目创建后不再修改，因此快照可以共享它。
+type entryLabels struct {
+	tags     []string
+	metadata map[string]string
 }
 
 // Valid 如果这不是零条目则返回 true。
 func (e Entry) Valid() bool { return e.ID != 0 }
 
-// byTime 是用于按时间排序条目数组的包装器
-// （零时间在末尾）。
-type byTime []*Entry
+// snapshot 返回条目的副本。
+func (e *Entry) snapshot() Entry {
+	s := *e
+	s.index = 0
+	return s
+}
+
+// Tags 返回条目的标签，由 WithTags 设置。返回的切片是副本。
+func (e Entry) Tags() []string {
+	if e.labels == nil {
+		return nil
+	}
+	return append([]string(nil), e.labels.tags...)
+}
+
+// Metadata 返回条目的元数据，由 WithMetadata 设置。返回的映射是副本。
+func (e Entry) Metadata() map[string]string {
+	if e.labels == nil || e.labels.metadata == nil {
+		return nil
+	}
+	m := make(map[string]string, len(e.labels.metadata))
+	for k, v := range e.labels.metadata {
+		m[k] = v
+	}
+	return m
+}
 
-func (s byTime) Len() int      { return len(s) }
-func (s byTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
-func (s byTime) Less(i, j int) bool {
+// copyLabels 返回条目标签和元数据的可修改副本。
+func (e *Entry) copyLabels() *entryLabels {
+	l := &entryLabels{}
+	if e.labels != nil {
+		l.tags = append([]string(nil), e.labels.tags...)
+		l.metadata = e.Metadata()
+	}
+	return l
+}
+
+// HasTag 如果条目带有给定的标签则返回 true。
+func (e Entry) HasTag(tag string) bool {
+	if e.labels == nil {
+		return false
+	}
+	for _, t := range e.labels.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// entryHeap 是按下一次激活时间排列的条目最小堆（零时间在末尾），
+// 实现 container/heap.Interface。每个条目在 index 字段中记录自己的位置，
+// 因此修改了 Next 的条目可以用 heap.Fix 在对数时间内调整。
+type entryHeap []*Entry
+
+func (h entryHeap) Len() int { return len(h) }
+
+func (h entryHeap) Less(i, j int) bool { return nextBefore(h[i], h[j]) }
+
+func (h entryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *entryHeap) Push(x interface{}) {
+	e := x.(*Entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *entryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
+
+// nextBefore 报告条目 a 是否应该在 b 之前激活。
+func nextBefore(a, b *Entry) bool {
 	// 两个零时间应该返回 false。
 	// 否则，零时间比任何其他时间都"大"。
 	//（将其排序到列表的末尾。）
-	if s[i].Next.IsZero() {
+	if a.Next.IsZero() {
 		return false
 	}
-	if s[j].Next.IsZero() {
+	if b.Next.IsZero() {
 		return true
 	}
-	return s[i].Next.Before(s[j].Next)
+	return a.Next.Before(b.Next)
 }
 
 // New 返回一个新的 Cron 作业运行器，由给定的选项修改。
@@ -107,6 +257,10 @@ func (s byTime) Less(i, j int) bool {
 //	  描述: 包装提交的作业以自定义行为。
 //	  默认值:     一个恢复恐慌并将其记录到 stderr 的链。
 //
+//	时钟
+//	  描述: 调度循环获取当前时间和创建定时器的来源。
+//	  默认值:     系统时钟
+//
 // 请参阅 "cron.With*" 来修改默认行为。
 func New(opts ...Option) *Cron {
 	c := &Cron{
@@ -115,13 +269,22 @@ func New(opts ...Option) *Cron {
 		add:       make(chan *Entry),
 		stop:      make(chan struct{}),
 		snapshot:  make(chan chan []Entry),
-		remove:    make(chan EntryID),
+		remove:    make(chan entryRemoval),
+		update:    make(chan entryUpdate),
+		lookup:    make(chan entryLookup),
+		index:     make(map[EntryID]*Entry),
 		running:   false,
 		runningMu: sync.Mutex{},
 		logger:    DefaultLogger,
 		location:  time.Local,
 		parser:    standardParser,
+		clock:     realClock{},
+		names:     make(map[string]EntryID),
+		jobs:      make(map[EntryID]int),
+		saves:     make(map[string]EntryState),
+		saveWake:  make(chan struct{}, 1),
 	}
+	c.jobCtx, c.jobCancel = context.WithCancel(context.Background())
 	for _, opt := range opts {
 		opt(c)
 	}
@@ -133,42 +296,194 @@ type FuncJob func()
 
 func (f FuncJob) Run() { f() }
 
+// FuncJobCtx 是将 func(context.Context) 转换为 cron.JobContext 的包装器
+type FuncJobCtx func(ctx context.Context)
+
+func (f FuncJobCtx) Run(ctx context.Context) { f(ctx) }
+
+// FuncErrJob 是将返回错误的 func(context.Context) error 转换为 cron.Job 的包装器。
+// 由 Cron 运行时，返回的错误会沿着 Chain 向外传递，并通过 Logger.Error 记录。
+type FuncErrJob func(ctx context.Context) error
+
+func (f FuncErrJob) Run() { _ = f(context.Background()) }
+
+func (f FuncErrJob) runContext(ctx context.Context) error { return f(ctx) }
+
+// ContextJob 将 JobContext 适配为 Job，使其可以被 Chain 包装并由 Cron 调度。
+// 由 Cron 运行时，作业会收到每次运行的上下文；直接调用 Run 时使用 context.Background()。
+type ContextJob struct {
+	Job JobContext
+}
+
+func (j ContextJob) Run() { j.Job.Run(context.Background()) }
+
+func (j ContextJob) runContext(ctx context.Context) error {
+	j.Job.Run(ctx)
+	return nil
+}
+
+// contextRunner 由能够接收每次运行上下文并报告错误的作业实现。
+type contextRunner interface {
+	runContext(ctx context.Context) error
+}
+
+// RunWithContext 使用给定的上下文运行 j，并返回它报告的错误。
+// 如果 j 能够接收上下文（例如 ContextJob、FuncErrJob 或此包中的包装器返回的作业），
+// 上下文会被传递下去；否则直接调用 j.Run() 并返回 nil。
+// 自定义 JobWrapper 应使用它来调用被包装的作业，以便上下文能够到达内层作业，
+// 并且内层作业返回的错误能够被观察到。
+func RunWithContext(ctx context.Context, j Job) error {
+	if cr, ok := j.(contextRunner); ok {
+		return cr.runContext(ctx)
+	}
+	j.Run()
+	return nil
+}
+
+// RunInfo 描述作业的一次运行。Cron 将它放入每次运行的上下文中，
+// 包装器和可感知上下文的作业可以使用 RunInfoFromContext 获取它。
+type RunInfo struct {
+	// ID 是运行的条目的 ID。
+	ID EntryID
+	// Name 是运行的条目的名称，未命名的条目为空。
+	Name string
+	// Scheduled 是这次运行对应的计划激活时间，手动触发的运行为零时间。
+	Scheduled time.Time
+	// Activation 标识这次运行对应的激活，在所有副本上都相同。它通常等于 Scheduled，
+	// 但对于 JitterSchedule 是加上随机延迟之前的时间。手动触发的运行为零时间。
+	Activation time.Time
+}
+
+type runInfoKey struct{}
+
+// RunInfoFromContext 返回 Cron 放入运行上下文中的 RunInfo。
+// 如果上下文不是由 Cron 为一次运行创建的，ok 为 false。
+func RunInfoFromContext(ctx context.Context) (info RunInfo, ok bool) {
+	info, ok = ctx.Value(runInfoKey{}).(RunInfo)
+	return info, ok
+}
+
 // AddFunc 向 Cron 添加一个函数，以在给定的计划上运行。
 // 使用此 Cron 实例的时区作为默认值来解析规范。
 // 返回一个不透明的 ID，可用于稍后删除它。
-func (c *Cron) AddFunc(spec string, cmd func()) (EntryID, error) {
-	return c.AddJob(spec, FuncJob(cmd))
+func (c *Cron) AddFunc(spec string, cmd func(), opts ...EntryOption) (EntryID, error) {
+	return c.AddJob(spec, FuncJob(cmd), opts...)
 }
 
 // AddJob 向 Cron 添加一个 Job，以在给定的计划上运行。
-// 使用此 Cron 实例的时区作为默认值来解析规范。
+// 使用此 Cron 实例的时区作为默认值来解析规范。如果解析器实现了
+// SeededScheduleParser，命名条目的名称被用作 "H" 字段的哈希种子。
 // 返回一个不透明的 ID，可用于稍后删除它。
-func (c *Cron) AddJob(spec string, cmd Job) (EntryID, error) {
-	schedule, err := c.parser.Parse(spec)
+func (c *Cron) AddJob(spec string, cmd Job, opts ...EntryOption) (EntryID, error) {
+	entry := c.newEntry(cmd, opts...)
+	schedule, err := c.parse(spec, entry.Name)
 	if err != nil {
 		return 0, err
 	}
-	return c.Schedule(schedule, cmd), nil
+	entry.Schedule = schedule
+	return c.addEntry(entry)
+}
+
+// AddNamedFunc 向 Cron 添加一个具有稳定名称的函数，以在给定的计划上运行。
+// 名称在此 Cron 实例中必须是唯一的，否则返回 ErrDuplicateName。
+// 之后可以使用 EntryByName 和 RemoveByName 通过名称访问该条目。
+func (c *Cron) AddNamedFunc(name, spec string, cmd func(), opts ...EntryOption) (EntryID, error) {
+	return c.AddNamedJob(name, spec, FuncJob(cmd), opts...)
+}
+
+// AddNamedJob 向 Cron 添加一个具有稳定名称的 Job，以在给定的计划上运行。
+// 名称在此 Cron 实例中必须是唯一的，否则返回 ErrDuplicateName。
+func (c *Cron) AddNamedJob(name, spec string, cmd Job, opts ...EntryOption) (EntryID, error) {
+	return c.AddJob(spec, cmd, append([]EntryOption{WithName(name)}, opts...)...)
+}
+
+// AddOnce 向 Cron 添加一个函数，在给定的时间只运行一次。
+// 条目在运行后自动删除。如果调度器启动或条目被添加时该时间已经过去，
+// 条目立即到期，并按照它的 misfire 策略处理。
+// 返回一个不透明的 ID，可用于在运行之前删除它。
+func (c *Cron) AddOnce(at time.Time, cmd func(), opts ...EntryOption) (EntryID, error) {
+	return c.schedule(At(at), FuncJob(cmd), opts...)
+}
+
+// AddFuncErr 向 Cron 添加一个返回错误的函数，以在给定的计划上运行。
+// 函数返回的错误会通过 Logger.Error 与条目 ID 一起记录。
+// 返回一个不透明的 ID，可用于稍后删除它。
+func (c *Cron) AddFuncErr(spec string, cmd func(ctx context.Context) error, opts ...EntryOption) (EntryID, error) {
+	return c.AddJob(spec, FuncErrJob(cmd), opts...)
+}
+
+// AddFuncCtx 向 Cron 添加一个可感知上下文的函数，以在给定的计划上运行。
+// 每次运行的上下文在 Cron 停止时被取消。
+// 返回一个不透明的 ID，可用于稍后删除它。
+func (c *Cron) AddFuncCtx(spec string, cmd func(ctx context.Context), opts ...EntryOption) (EntryID, error) {
+	return c.AddJobCtx(spec, FuncJobCtx(cmd), opts...)
+}
+
+// AddJobCtx 向 Cron 添加一个 JobContext，以在给定的计划上运行。
+// 使用此 Cron 实例的时区作为默认值来解析规范。
+// 返回一个不透明的 ID，可用于稍后删除它。
+func (c *Cron) AddJobCtx(spec string, cmd JobContext, opts ...EntryOption) (EntryID, error) {
+	return c.AddJob(spec, ContextJob{cmd}, opts...)
+}
+
+// ScheduleCtx 向 Cron 添加一个 JobContext，以在给定的计划上运行。
+// 作业被适配为 ContextJob 并使用配置的链进行包装。
+func (c *Cron) ScheduleCtx(schedule Schedule, cmd JobContext, opts ...EntryOption) EntryID {
+	return c.Schedule(schedule, ContextJob{cmd}, opts...)
 }
 
 // Schedule 向 Cron 添加一个 Job，以在给定的计划上运行。
-// 作业使用配置的链进行包装。
-func (c *Cron) Schedule(schedule Schedule, cmd Job) EntryID {
+// 作业使用配置的链进行包装，然后按顺序应用条目选项。
+// 如果条目的名称已被使用，则记录错误并返回零 ID；
+// 需要处理该错误的调用者应使用 AddJob 或 AddNamedJob。
+func (c *Cron) Schedule(schedule Schedule, cmd Job, opts ...EntryOption) EntryID {
+	id, err := c.schedule(schedule, cmd, opts...)
+	if err != nil {
+		c.logger.Error(err, "failed to schedule entry")
+	}
+	return id
+}
+
+func (c *Cron) schedule(schedule Schedule, cmd Job, opts ...EntryOption) (EntryID, error) {
+	entry := c.newEntry(cmd, opts...)
+	entry.Schedule = schedule
+	return c.addEntry(entry)
+}
+
+// newEntry 返回一个运行 cmd 的新条目，它的作业使用配置的链包装，并按顺序应用了条目选项。
+// 每个选项只被应用一次。
+func (c *Cron) newEntry(cmd Job, opts ...EntryOption) *Entry {
+	entry := &Entry{
+		Job:           cmd,
+		MisfirePolicy: c.misfirePolicy,
+	}
+	for _, opt := range opts {
+		opt(entry)
+	}
+	entry.WrappedJob = c.wrapJob(entry, cmd)
+	return entry
+}
+
+// addEntry 为条目分配 ID 和名称，并将它加入 Cron。
+func (c *Cron) addEntry(entry *Entry) (EntryID, error) {
 	c.runningMu.Lock()
-	defer c.runningMu.Unlock()
+	if err := c.reserveName(entry.Name, c.nextID+1); err != nil {
+		c.runningMu.Unlock()
+		return 0, err
+	}
 	c.nextID++
-	entry := &Entry{
-		ID:         c.nextID,
-		Schedule:   schedule,
-		WrappedJob: c.chain.Then(cmd),
-		Job:        cmd,
-	}
-	if !c.running {
-		c.entries = append(c.entries, entry)
-	} else {
+	entry.ID = c.nextID
+	if c.running {
 		c.add <- entry
+		c.runningMu.Unlock()
+		return entry.ID, nil
 	}
-	return entry.ID
+	c.insertEntry(entry)
+	added := entry.snapshot()
+	c.runningMu.Unlock()
+	// 在锁之外通知监听器。
+	c.notifyEntryAdded(added)
+	return entry.ID, nil
 }
 
 // Entries 返回 cron 条目的快照。
@@ -188,25 +503,146 @@ func (c *Cron) Location() *time.Location {
 	return c.location
 }
 
-// Entry 返回给定条目的快照，如果找不到则返回 nil。
+// Entry 返回给定条目的快照，如果找不到则返回零条目。
 func (c *Cron) Entry(id EntryID) Entry {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	if c.running {
+		reply := make(chan Entry, 1)
+		c.lookup <- entryLookup{id, reply}
+		return <-reply
+	}
+	return c.entryByID(id)
+}
+
+// EntryByName 返回具有给定名称的条目的快照，如果找不到则返回零条目。
+func (c *Cron) EntryByName(name string) Entry {
+	id, ok := c.lookupName(name)
+	if !ok {
+		return Entry{}
+	}
+	return c.Entry(id)
+}
+
+// EntriesWithTag 返回带有给定标签的条目的快照。
+func (c *Cron) EntriesWithTag(tag string) []Entry {
+	var entries []Entry
 	for _, entry := range c.Entries() {
-		if id == entry.ID {
-			return entry
+		if entry.HasTag(tag) {
+			entries = append(entries, entry)
 		}
 	}
-	return Entry{}
+	return entries
+}
+
+// RemoveByName 从将来运行中删除具有给定名称的条目。
+func (c *Cron) RemoveByName(name string) {
+	if id, ok := c.lookupName(name); ok {
+		c.Remove(id)
+	}
 }
 
-// Remove 从将来运行中删除一个条目。
+// Remove 从将来运行中删除一个条目。Remove 返回时条目已被删除，
+// 它的名称可以立即被新的条目使用。
 func (c *Cron) Remove(id EntryID) {
 	c.runningMu.Lock()
-	defer c.runningMu.Unlock()
 	if c.running {
-		c.remove <- id
-	} else {
-		c.removeEntry(id)
+		reply := make(chan struct{})
+		c.remove <- entryRemoval{id, reply}
+		<-reply
+		c.runningMu.Unlock()
+		return
 	}
+	e := c.removeEntry(id)
+	c.runningMu.Unlock()
+	// 在锁之外通知监听器。
+	if e != nil {
+		c.notifyEntryRemoved(e.snapshot())
+	}
+}
+
+// Pause 暂停一个条目，直到调用 Resume。与 Remove 不同，
+// 暂停的条目保留它的 ID、计划和上次运行时间。
+// 如果找不到条目则返回 ErrEntryNotFound。
+func (c *Cron) Pause(id EntryID) error {
+	return c.updateEntry(id, func(e *Entry, now time.Time) {
+		e.Paused = true
+		e.Next = time.Time{}
+		c.logger.Info("paused", "now", now, "entry", e.ID)
+	})
+}
+
+// Resume 恢复一个已暂停的条目，并从现在开始重新计算它的下一次激活时间。
+// 暂停期间时间已经过去的一次性条目立即到期，由它的 misfire 策略决定是否运行；
+// 超出生命周期限制的条目被删除。
+// 恢复未暂停的条目是无操作。如果找不到条目则返回 ErrEntryNotFound。
+func (c *Cron) Resume(id EntryID) error {
+	return c.updateEntry(id, func(e *Entry, now time.Time) {
+		if !e.Paused {
+			return
+		}
+		e.Paused = false
+		e.Next = e.resumeNext(now)
+		c.logger.Info("resumed", "now", now, "entry", e.ID, "next", e.Next)
+	})
+}
+
+// Reschedule 将条目的计划替换为给定规范解析得到的计划，并立即重新计算它的下一次激活时间。
+// 条目保留它的 ID 和上次运行时间。使用此 Cron 实例的时区作为默认值来解析规范。
+// 如果规范无效则返回解析错误，如果找不到条目则返回 ErrEntryNotFound。
+func (c *Cron) Reschedule(id EntryID, spec string) error {
+	schedule, err := c.parse(spec, c.Entry(id).Name)
+	if err != nil {
+		return err
+	}
+	return c.UpdateSchedule(id, schedule)
+}
+
+// UpdateSchedule 原子地替换条目的计划，并立即重新计算它的下一次激活时间。
+// 暂停的条目在恢复时才会计算下一次激活时间。
+// 如果找不到条目则返回 ErrEntryNotFound。
+func (c *Cron) UpdateSchedule(id EntryID, schedule Schedule) error {
+	return c.updateEntry(id, func(e *Entry, now time.Time) {
+		e.Schedule = schedule
+		if !e.Paused {
+			e.Next = e.resumeNext(now)
+		}
+		c.logger.Info("rescheduled", "now", now, "entry", e.ID, "next", e.Next)
+	})
+}
+
+// ReplaceJob 原子地替换条目的作业。新作业像添加时一样被包装，
+// 并从下一次激活开始运行；已经在运行的作业不受影响。
+// 如果找不到条目则返回 ErrEntryNotFound。
+func (c *Cron) ReplaceJob(id EntryID, cmd Job) error {
+	return c.updateEntry(id, func(e *Entry, now time.Time) {
+		e.Job = cmd
+		e.WrappedJob = c.wrapJob(e, cmd)
+		c.logger.Info("replaced", "now", now, "entry", e.ID)
+	})
+}
+
+// RunNow 立即运行条目的作业，而不影响它的计划。
+// 作业通过配置的链运行（例如 SkipIfStillRunning 仍然生效），
+// 并且与按计划运行的作业一样，Stop 返回的上下文会等待它完成。
+// 如果找不到条目则返回 ErrEntryNotFound。
+func (c *Cron) RunNow(id EntryID) error {
+	return c.Trigger(id, false)
+}
+
+// Trigger 立即运行条目的作业。如果 resetNext 为 true，则从现在开始重新计算
+// 条目的下一次激活时间，这样刚刚手动运行的作业不会很快再次按计划运行。
+// 暂停的条目也可以被触发，但它的下一次激活时间保持为零。
+// 使用 WithLeaderElector 时，手动触发的运行在跟随者上同样运行。
+// 如果找不到条目则返回 ErrEntryNotFound。
+func (c *Cron) Trigger(id EntryID, resetNext bool) error {
+	return c.updateEntry(id, func(e *Entry, now time.Time) {
+		c.startJob(e, time.Time{})
+		if resetNext && !e.Paused {
+			e.Next = e.next(now)
+		}
+		c.logger.Info("triggered", "now", now, "entry", e.ID, "next", e.Next)
+	})
 }
 
 // Start 在自己的 goroutine 中启动 cron 调度器，如果已经启动则为无操作。
@@ -237,64 +673,112 @@ func (c *Cron) Run() {
 func (c *Cron) run() {
 	c.logger.Info("start")
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if c.elector != nil {
+		c.elect(ctx)
+	}
+	if c.store != nil {
+		c.saveStates(ctx)
+	}
+
 	// 计算每个条目的下一次激活时间。
 	now := c.now()
 	for _, entry := range c.entries {
-		entry.Next = entry.Schedule.Next(now)
+		c.initEntry(entry, now)
 		c.logger.Info("schedule", "now", now, "entry", entry.ID, "next", entry.Next)
 	}
+	heap.Init(&c.entries)
+	for _, l := range c.listeners {
+		l.OnSchedulerStart()
+	}
+	for _, e := range c.endedEntries() {
+		c.removeEnded(e)
+	}
 
 	for {
-		// 确定要运行的下一个条目。
-		sort.Sort(byTime(c.entries))
-
-		var timer *time.Timer
+		// 堆顶是要运行的下一个条目。
+		var timer Timer
 		if len(c.entries) == 0 || c.entries[0].Next.IsZero() {
 			// 如果还没有条目，就睡眠 - 它仍然处理新条目
 			// 和停止请求。
-			timer = time.NewTimer(100000 * time.Hour)
+			timer = c.clock.NewTimer(100000 * time.Hour)
 		} else {
-			timer = time.NewTimer(c.entries[0].Next.Sub(now))
+			timer = c.clock.NewTimer(c.entries[0].Next.Sub(now))
 		}
 
 		for {
 			select {
-			case now = <-timer.C:
+			case now = <-timer.C():
 				now = now.In(c.location)
 				c.logger.Info("wake", "now", now)
 
 				// 运行下一次时间小于现在的每个条目
-				for _, e := range c.entries {
+				var finished []*Entry
+				for len(c.entries) > 0 {
+					e := c.entries[0]
 					if e.Next.After(now) || e.Next.IsZero() {
 						break
 					}
-					c.startJob(e.WrappedJob)
-					e.Prev = e.Next
-					e.Next = e.Schedule.Next(now)
+					c.fire(e, now)
+					heap.Fix(&c.entries, e.index)
 					c.logger.Info("run", "now", now, "entry", e.ID, "next", e.Next)
+					if e.ended() {
+						finished = append(finished, e)
+					}
+				}
+
+				// 运行后结束的条目（例如一次性条目）永远不会再运行。
+				for _, e := range finished {
+					c.removeEnded(e)
 				}
 
 			case newEntry := <-c.add:
 				timer.Stop()
 				now = c.now()
-				newEntry.Next = newEntry.Schedule.Next(now)
-				c.entries = append(c.entries, newEntry)
+				c.initEntry(newEntry, now)
+				c.insertEntry(newEntry)
 				c.logger.Info("added", "now", now, "entry", newEntry.ID, "next", newEntry.Next)
+				c.notifyEntryAdded(newEntry.snapshot())
+				if newEntry.ended() {
+					c.removeEnded(newEntry)
+				}
 
 			case replyChan := <-c.snapshot:
 				replyChan <- c.entrySnapshot()
 				continue
 
+			case lookup := <-c.lookup:
+				lookup.reply <- c.entryByID(lookup.id)
+				continue
+
 			case <-c.stop:
 				timer.Stop()
 				c.logger.Info("stop")
 				return
 
-			case id := <-c.remove:
+			case removal := <-c.remove:
+				timer.Stop()
+				now = c.now()
+				e := c.removeEntry(removal.id)
+				close(removal.reply)
+				if e != nil {
+					c.notifyEntryRemoved(e.snapshot())
+				}
+				c.logger.Info("removed", "entry", removal.id)
+
+			case update := <-c.update:
 				timer.Stop()
 				now = c.now()
-				c.removeEntry(id)
-				c.logger.Info("removed", "entry", id)
+				e := c.findEntry(update.id)
+				if e != nil {
+					update.apply(e, now)
+					heap.Fix(&c.entries, e.index)
+				}
+				if e != nil && e.ended() {
+					c.removeEnded(e)
+				}
+				update.reply <- e != nil
 			}
 
 			break
@@ -302,29 +786,117 @@ func (c *Cron) run() {
 	}
 }
 
-// startJob 在新的 goroutine 中运行给定的作业。
-func (c *Cron) startJob(j Job) {
+// startJob 在新的 goroutine 中运行给定条目的作业。scheduled 是这次运行
+// 对应的计划激活时间，手动触发的运行为零时间。
+// 作业收到的上下文携带 RunInfo，并在 Cron 停止或达到配置的运行截止时间时被取消，
+// 作业返回的错误使用条目 ID 记录。
+func (c *Cron) startJob(e *Entry, scheduled time.Time) {
+	id, j := e.ID, e.WrappedJob
+	var event JobEvent
+	if len(c.listeners) > 0 {
+		event = JobEvent{Entry: e.snapshot(), Scheduled: scheduled}
+	}
+	ctx, cancel := c.jobContext()
+	activation := scheduled
+	if s, ok := e.Schedule.(activationSchedule); ok && !scheduled.IsZero() {
+		activation = s.activation(scheduled)
+	}
+	ctx = context.WithValue(ctx, runInfoKey{}, RunInfo{ID: id, Name: e.Name, Scheduled: scheduled, Activation: activation})
 	c.jobWaiter.Add(1)
+	c.trackJob(id, 1)
 	go func() {
 		defer c.jobWaiter.Done()
-		j.Run()
+		defer c.trackJob(id, -1)
+		defer cancel()
+		if err := c.runJob(ctx, j, event); err != nil {
+			// Recover 已经记录了 panic 的详细信息，Timeout 已经记录了超时，跳过的运行不是错误。
+			var panicErr *PanicError
+			if !errors.As(err, &panicErr) && !errors.Is(err, ErrSkipped) && !errors.Is(err, ErrTimeout) {
+				c.logger.Error(err, "job failed", "entry", id)
+			}
+		}
 	}()
 }
 
+// jobContext 返回单次运行的上下文。
+func (c *Cron) jobContext() (context.Context, context.CancelFunc) {
+	if c.jobTimeout > 0 {
+		return context.WithTimeout(c.jobCtx, c.jobTimeout)
+	}
+	return context.WithCancel(c.jobCtx)
+}
+
 // now 返回 c 位置的当前时间
 func (c *Cron) now() time.Time {
-	return time2.Now().In(c.location)
+	return c.clock.Now().In(c.location)
 }
 
 // Stop 如果 cron 调度器正在运行则停止它；否则什么也不做。
+// 正在运行的作业收到的上下文会被取消，但 Stop 不会强制终止它们。
 // 返回一个上下文，以便调用者可以等待正在运行的作业完成。
 func (c *Cron) Stop() context.Context {
+	c.halt()()
+	return c.waitJobs()
+}
+
+// StopWithContext 停止调度器，并在不取消作业上下文的情况下等待正在运行的作业完成，
+// 类似于 http.Server 的 Shutdown。如果所有作业在 ctx 结束之前完成，则返回 nil。
+// 否则它取消仍在运行的作业收到的上下文，并立即返回一个 *StopError，
+// 其中列出了这些作业所属的条目。
+func (c *Cron) StopWithContext(ctx context.Context) error {
+	cancelJobs := c.halt()
+	defer cancelJobs()
+	select {
+	case <-c.waitJobs().Done():
+		return nil
+	case <-ctx.Done():
+	}
+	running := c.runningEntries()
+	c.logger.Info("stop timed out", "running", running)
+	return &StopError{Running: running, Err: ctx.Err()}
+}
+
+// StopError 由 StopWithContext 在作业未能及时完成时返回。
+type StopError struct {
+	// Running 是停止时仍有作业在运行的条目的 ID，按升序排列。
+	Running []EntryID
+	// Err 是导致等待结束的上下文错误。
+	Err error
+}
+
+func (e *StopError) Error() string {
+	return fmt.Sprintf("cron: %d entries still running: %v: %v", len(e.Running), e.Running, e.Err)
+}
+
+// Unwrap 返回上下文错误，使 errors.Is(err, context.DeadlineExceeded) 可以工作。
+func (e *StopError) Unwrap() error {
+	return e.Err
+}
+
+// halt 停止调度循环，并为之后的启动准备新的作业上下文。
+// 它返回取消已经启动的作业的上下文的函数。
+func (c *Cron) halt() context.CancelFunc {
 	c.runningMu.Lock()
-	defer c.runningMu.Unlock()
+	stopped := c.running
 	if c.running {
 		c.stop <- struct{}{}
 		c.running = false
 	}
+	cancel := c.jobCancel
+	c.jobCtx, c.jobCancel = context.WithCancel(context.Background())
+	c.runningMu.Unlock()
+
+	// 在锁之外通知监听器，使它们可以调用 Cron 的方法。
+	if stopped {
+		for _, l := range c.listeners {
+			l.OnSchedulerStop()
+		}
+	}
+	return cancel
+}
+
+// waitJobs 返回一个在所有正在运行的作业完成时结束的上下文。
+func (c *Cron) waitJobs() context.Context {
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
 		c.jobWaiter.Wait()
@@ -333,21 +905,249 @@ func (c *Cron) Stop() context.Context {
 	return ctx
 }
 
-// entrySnapshot 返回当前 cron 条目列表的副本。
+// waitGroup 类似于 sync.WaitGroup，但允许在 Wait 返回之前再次调用 Add：
+// 调度器停止后可以立即重新启动，而上一次 Stop 返回的上下文仍在等待。
+// Wait 在计数变为零时返回，不等待之后开始的工作。
+type waitGroup struct {
+	mu   sync.Mutex
+	n    int
+	done chan struct{}
+}
+
+// Add 将计数加上 delta。
+func (w *waitGroup) Add(delta int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.n == 0 && delta > 0 {
+		w.done = make(chan struct{})
+	}
+	w.n += delta
+	if w.n < 0 {
+		panic("cron: negative wait group counter")
+	}
+	if w.n == 0 && delta < 0 {
+		close(w.done)
+	}
+}
+
+// Done 将计数减一。
+func (w *waitGroup) Done() {
+	w.Add(-1)
+}
+
+// Wait 等待计数变为零。
+func (w *waitGroup) Wait() {
+	w.mu.Lock()
+	done := w.done
+	w.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// trackJob 将条目正在运行的作业数量加上 delta。
+func (c *Cron) trackJob(id EntryID, delta int) {
+	c.jobsMu.Lock()
+	defer c.jobsMu.Unlock()
+	c.jobs[id] += delta
+	if c.jobs[id] <= 0 {
+		delete(c.jobs, id)
+	}
+}
+
+// runningEntries 返回有作业正在运行的条目的 ID，按升序排列。
+func (c *Cron) runningEntries() []EntryID {
+	c.jobsMu.Lock()
+	defer c.jobsMu.Unlock()
+	ids := make([]EntryID, 0, len(c.jobs))
+	for id := range c.jobs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// entrySnapshot 返回当前 cron 条目列表的副本，按下一次激活时间排序，
+// 激活时间相同的条目按添加的顺序排列。
 func (c *Cron) entrySnapshot() []Entry {
 	var entries = make([]Entry, len(c.entries))
 	for i, e := range c.entries {
-		entries[i] = *e
+		entries[i] = e.snapshot()
 	}
+	sort.Slice(entries, func(i, j int) bool {
+		if nextBefore(&entries[i], &entries[j]) {
+			return true
+		}
+		if nextBefore(&entries[j], &entries[i]) {
+			return false
+		}
+		return entries[i].ID < entries[j].ID
+	})
 	return entries
 }
 
-func (c *Cron) removeEntry(id EntryID) {
-	var entries []*Entry
+// entryByID 返回给定条目的快照，如果找不到则返回零条目。
+func (c *Cron) entryByID(id EntryID) Entry {
+	if e := c.findEntry(id); e != nil {
+		return e.snapshot()
+	}
+	return Entry{}
+}
+
+// parse 使用配置的解析器解析规范。如果解析器支持哈希种子，
+// 条目名称被用作种子，使 "H" 字段在重启后保持稳定。
+func (c *Cron) parse(spec, name string) (Schedule, error) {
+	if p, ok := c.parser.(SeededScheduleParser); ok && name != "" {
+		return p.ParseWithSeed(spec, name)
+	}
+	return c.parser.Parse(spec)
+}
+
+// wrapJob 使用配置的链包装条目的作业，并应用全局工作池和条目自己的并发上限。
+// 如果配置了 Locker，分布式锁在最外层获取，使没有获得锁的副本不占用工作槽或并发名额，
+// 并且在工作池中排队的运行已经持有本次激活的锁，其他副本不会在它等待期间再次运行这次激活。
+func (c *Cron) wrapJob(e *Entry, cmd Job) Job {
+	j := c.chain.Then(cmd)
+	if c.pool != nil {
+		j = c.pool.wrap(j, c.logger)
+	}
+	if e.MaxConcurrent > 0 {
+		j = LimitConcurrency(e.MaxConcurrent, c.logger)(j)
+	}
+	if c.locker != nil {
+		j = DistributedLock(c.locker, c.lockTTL, c.logger)(j)
+	}
+	return j
+}
+
+// entryLookup 是发送给运行循环的获取单个条目快照的请求。
+type entryLookup struct {
+	id    EntryID
+	reply chan Entry
+}
+
+// entryRemoval 是发送给运行循环的删除单个条目的请求，删除之后 reply 被关闭。
+type entryRemoval struct {
+	id    EntryID
+	reply chan struct{}
+}
+
+// entryUpdate 是发送给运行循环的修改单个条目的请求。
+type entryUpdate struct {
+	id    EntryID
+	apply func(e *Entry, now time.Time)
+	reply chan bool
+}
+
+// updateEntry 将 apply 应用于给定 ID 的条目。如果调度器正在运行，
+// 修改在运行循环中进行，因此相对于激活是原子的，并且下一次唤醒时间会被重新计算。
+// 如果调度器正在运行，修改之后结束的条目被删除；否则在调度器启动时删除。
+// 如果找不到条目则返回 ErrEntryNotFound。
+func (c *Cron) updateEntry(id EntryID, apply func(e *Entry, now time.Time)) error {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	if c.running {
+		reply := make(chan bool, 1)
+		c.update <- entryUpdate{id, apply, reply}
+		if !<-reply {
+			return ErrEntryNotFound
+		}
+		return nil
+	}
+	e := c.findEntry(id)
+	if e == nil {
+		return ErrEntryNotFound
+	}
+	apply(e, c.now())
+	heap.Fix(&c.entries, e.index)
+	return nil
+}
+
+// initEntry 计算条目在调度器启动或条目被添加时的首次激活时间。
+func (c *Cron) initEntry(e *Entry, now time.Time) {
+	c.restoreEntry(e, now)
+	if e.Next.IsZero() {
+		e.Next = e.overdueNext()
+	}
+	if e.Paused {
+		e.Next = time.Time{}
+	}
+}
+
+// endedEntries 返回永远不会再激活的条目。
+func (c *Cron) endedEntries() []*Entry {
+	var ended []*Entry
 	for _, e := range c.entries {
-		if e.ID != id {
-			entries = append(entries, e)
+		if e.ended() {
+			ended = append(ended, e)
 		}
 	}
-	c.entries = entries
+	return ended
+}
+
+// removeEnded 在运行循环中删除一个永远不会再激活的条目，并通知监听器。
+func (c *Cron) removeEnded(e *Entry) {
+	if c.removeEntry(e.ID) != nil {
+		c.notifyEntryRemoved(e.snapshot())
+	}
+	c.logger.Info("removed", "entry", e.ID)
+}
+
+// findEntry 返回给定 ID 的条目，如果找不到则返回 nil。
+func (c *Cron) findEntry(id EntryID) *Entry {
+	return c.index[id]
+}
+
+// insertEntry 将条目加入堆和 ID 索引。
+func (c *Cron) insertEntry(e *Entry) {
+	heap.Push(&c.entries, e)
+	c.index[e.ID] = e
+}
+
+// removeEntry 从堆和 ID 索引中删除条目，并释放它的名称。
+// 它返回被删除的条目，如果找不到则返回 nil；调用者负责通知监听器。
+func (c *Cron) removeEntry(id EntryID) *Entry {
+	e, ok := c.index[id]
+	if !ok {
+		return nil
+	}
+	heap.Remove(&c.entries, e.index)
+	delete(c.index, id)
+	c.releaseName(e.Name, e.ID)
+	return e
+}
+
+// reserveName 将名称分配给给定的条目 ID，如果名称已被其他条目使用则返回错误。
+// 空名称总是被接受。
+func (c *Cron) reserveName(name string, id EntryID) error {
+	if name == "" {
+		return nil
+	}
+	c.namesMu.Lock()
+	defer c.namesMu.Unlock()
+	if _, ok := c.names[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateName, name)
+	}
+	c.names[name] = id
+	return nil
+}
+
+// releaseName 在名称仍属于给定条目时释放它。
+func (c *Cron) releaseName(name string, id EntryID) {
+	if name == "" {
+		return
+	}
+	c.namesMu.Lock()
+	defer c.namesMu.Unlock()
+	if c.names[name] == id {
+		delete(c.names, name)
+	}
+}
+
+// lookupName 返回具有给定名称的条目的 ID。
+func (c *Cron) lookupName(name string) (EntryID, bool) {
+	c.namesMu.Lock()
+	defer c.namesMu.Unlock()
+	id, ok := c.names[name]
+	return id, ok
 }
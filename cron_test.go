@@ -2,8 +2,11 @@ package cron
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"reflect"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -670,6 +673,248 @@ func TestStopAndWait(t *testing.T) {
 	})
 }
 
+// 测试可感知上下文的作业在 Stop 时收到取消信号。
+func TestJobContextCancelledOnStop(t *testing.T) {
+	started := make(chan struct{})
+	done := make(chan error, 1)
+
+	cron := newWithSeconds()
+	cron.AddFuncCtx("* * * * * ?", func(ctx context.Context) {
+		select {
+		case started <- struct{}{}:
+		default:
+			return
+		}
+		<-ctx.Done()
+		done <- ctx.Err()
+	})
+	cron.Start()
+
+	select {
+	case <-started:
+	case <-time.After(OneSecond):
+		t.Fatal("expected job runs")
+	}
+
+	ctx := cron.Stop()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(OneSecond):
+		t.Fatal("expected job context to be cancelled on Stop")
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(OneSecond):
+		t.Error("expected Stop context to be done once the job returned")
+	}
+}
+
+// 测试 StopWithContext 在不取消作业上下文的情况下等待作业完成。
+func TestStopWithContext(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	started := make(chan struct{})
+	finished := make(chan error, 1)
+	cron.AddFuncCtx("@hourly", func(ctx context.Context) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		finished <- ctx.Err()
+	})
+	cron.Start()
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), OneSecond)
+	defer cancel()
+	if err := cron.StopWithContext(ctx); err != nil {
+		t.Fatalf("expected the job to finish in time, got %v", err)
+	}
+	if err := <-finished; err != nil {
+		t.Errorf("expected the job context to stay alive while waiting, got %v", err)
+	}
+}
+
+// 测试 StopWithContext 在截止时间到达后取消作业，并报告仍在运行的条目。
+func TestStopWithContextDeadline(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	started := make(chan struct{}, 2)
+	cancelled := make(chan error, 2)
+	job := func(ctx context.Context) {
+		started <- struct{}{}
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+	}
+	slow1, _ := cron.AddFuncCtx("@hourly", job)
+	slow2, _ := cron.AddFuncCtx("@hourly", job)
+	cron.Start()
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	<-started
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := cron.StopWithContext(ctx)
+	var stopErr *StopError
+	if !errors.As(err, &stopErr) {
+		t.Fatalf("expected a *StopError, got %v", err)
+	}
+	if expected := []EntryID{slow1, slow2}; !reflect.DeepEqual(stopErr.Running, expected) {
+		t.Errorf("expected running entries %v, got %v", expected, stopErr.Running)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the error to wrap the context error, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-cancelled:
+			if err != context.Canceled {
+				t.Errorf("expected context.Canceled, got %v", err)
+			}
+		case <-time.After(OneSecond):
+			t.Fatal("expected running jobs to be cancelled after the deadline")
+		}
+	}
+}
+
+// 测试 WithJobTimeout 为每次运行的上下文设置截止时间。
+func TestJobContextTimeout(t *testing.T) {
+	done := make(chan error, 1)
+
+	cron := New(WithParser(secondParser), WithChain(), WithJobTimeout(10*time.Millisecond))
+	cron.ScheduleCtx(Every(time.Second), FuncJobCtx(func(ctx context.Context) {
+		<-ctx.Done()
+		select {
+		case done <- ctx.Err():
+		default:
+		}
+	}))
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case err := <-done:
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+	case <-time.After(2 * OneSecond):
+		t.Fatal("expected job context to reach its deadline")
+	}
+}
+
+// 测试作业返回的错误会与条目 ID 一起被记录。
+func TestJobErrorIsLogged(t *testing.T) {
+	var buf syncWriter
+	cron := New(WithParser(secondParser), WithChain(), WithLogger(newBufLogger(&buf)))
+	id, _ := cron.AddFuncErr("* * * * * ?", func(context.Context) error {
+		return errors.New("boom")
+	})
+	cron.Start()
+	defer cron.Stop()
+
+	time.Sleep(OneSecond)
+	out := buf.String()
+	if !strings.Contains(out, "job failed") ||
+		!strings.Contains(out, "error=boom") ||
+		!strings.Contains(out, fmt.Sprintf("entry=%d", id)) {
+		t.Error("expected the job error to be logged, got:", out)
+	}
+}
+
+// 测试 Timeout 记录的超时不会被 Cron 再次记录。
+func TestJobTimeoutIsLoggedOnce(t *testing.T) {
+	var buf syncWriter
+	logger := newBufLogger(&buf)
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start, WithChain(Timeout(10*time.Millisecond, logger)), WithLogger(logger))
+	timedOut := make(chan struct{})
+	cron.AddFuncCtx("@hourly", func(ctx context.Context) {
+		<-ctx.Done()
+		close(timedOut)
+	})
+	cron.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	<-timedOut
+	<-cron.Stop().Done()
+	out := buf.String()
+	if strings.Count(out, "job timed out") != 1 || strings.Contains(out, "job failed") {
+		t.Error("expected the timeout to be logged once, got:", out)
+	}
+}
+
+func TestNamedEntries(t *testing.T) {
+	cron := newWithSeconds()
+	id, err := cron.AddNamedFunc("backup", "0 0 0 1 1 ?", func() {},
+		WithTags("ops", "nightly"), WithMetadata(map[string]string{"owner": "storage"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cron.AddNamedFunc("report", "0 0 0 1 1 ?", func() {}, WithTags("nightly"))
+	cron.AddFunc("0 0 0 1 1 ?", func() {}, WithTags("ops"))
+
+	if _, err := cron.AddNamedFunc("backup", "* * * * * ?", func() {}); !errors.Is(err, ErrDuplicateName) {
+		t.Errorf("expected ErrDuplicateName, got %v", err)
+	}
+	if id := cron.Schedule(Every(time.Second), FuncJob(func() {}), WithName("backup")); id != 0 {
+		t.Errorf("expected zero id for a duplicate name, got %d", id)
+	}
+
+	check := func(t *testing.T) {
+		entry := cron.EntryByName("backup")
+		if entry.ID != id || entry.Name != "backup" || entry.Metadata()["owner"] != "storage" {
+			t.Errorf("unexpected entry: %+v", entry)
+		}
+		if entries := cron.EntriesWithTag("nightly"); len(entries) != 2 {
+			t.Errorf("expected 2 nightly entries, got %d", len(entries))
+		}
+		if entries := cron.EntriesWithTag("ops"); len(entries) != 2 {
+			t.Errorf("expected 2 ops entries, got %d", len(entries))
+		}
+		if cron.EntryByName("missing") != (Entry{}) {
+			t.Error("expected the zero entry for an unknown name")
+		}
+		if tags := cron.EntryByName("backup").Tags(); len(tags) != 2 || tags[0] != "ops" {
+			t.Errorf("unexpected tags: %v", tags)
+		}
+	}
+
+	t.Run("before start", check)
+	cron.Start()
+	defer cron.Stop()
+	t.Run("while running", check)
+
+	cron.RemoveByName("backup")
+	if cron.EntryByName("backup").Valid() {
+		t.Error("expected entry to be removed")
+	}
+	if len(cron.Entries()) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(cron.Entries()))
+	}
+
+	// 删除后名称可以被重新使用。
+	if _, err := cron.AddNamedFunc("backup", "0 0 0 1 1 ?", func() {}); err != nil {
+		t.Errorf("expected name to be reusable after removal, got %v", err)
+	}
+
+	// Remove 返回之前名称已经被释放。
+	for i := 0; i < 1000; i++ {
+		cron.RemoveByName("backup")
+		if _, err := cron.AddNamedFunc("backup", "0 0 0 1 1 ?", func() {}); err != nil {
+			t.Fatalf("iteration %d: expected name to be reusable after removal, got %v", i, err)
+		}
+	}
+}
+
 func TestMultiThreadedStartAndStop(t *testing.T) {
 	cron := New()
 	go cron.Run()
@@ -699,3 +944,231 @@ func stop(cron *Cron) chan bool {
 func newWithSeconds() *Cron {
 	return New(WithParser(secondParser), WithChain())
 }
+
+// newWithFakeClock 返回一个在 UTC 中由从 start 开始的假时钟驱动的 Cron，
+// 它没有作业包装器，并应用了给定的选项。
+func newWithFakeClock(start time.Time, opts ...Option) (*Cron, *FakeClock) {
+	clock := NewFakeClock(start)
+	opts = append([]Option{WithClock(clock), WithLocation(time.UTC), WithChain()}, opts...)
+	return New(opts...), clock
+}
+
+func TestPauseAndResume(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	var calls int64
+	id, _ := cron.AddFunc("@hourly", func() { atomic.AddInt64(&calls, 1) })
+	if err := cron.Pause(id); err != nil {
+		t.Fatal(err)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	entry := cron.Entry(id)
+	if !entry.Paused || !entry.Next.IsZero() {
+		t.Errorf("expected paused entry with zero next, got %+v", entry)
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	clock.BlockUntil(1)
+	if err := cron.Resume(id); err != nil {
+		t.Fatal(err)
+	}
+	entry = cron.Entry(id)
+	if entry.Paused {
+		t.Error("expected entry to be resumed")
+	}
+	if expected := time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC); !entry.Next.Equal(expected) {
+		t.Errorf("expected next %v, got %v", expected, entry.Next)
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	clock.BlockUntil(1)
+	if err := cron.Pause(id); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Hour)
+	<-cron.Stop().Done()
+	if n := atomic.LoadInt64(&calls); n != 1 {
+		t.Errorf("expected job runs once while resumed, got %d", n)
+	}
+
+	if err := cron.Pause(id + 1); err != ErrEntryNotFound {
+		t.Errorf("expected ErrEntryNotFound, got %v", err)
+	}
+}
+
+func TestUpdateScheduleAndJob(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	ran := make(chan string, 10)
+	id, _ := cron.AddFunc("@daily", func() { ran <- "old" })
+	if err := cron.Reschedule(id, "bogus"); err == nil {
+		t.Error("expected an error for an invalid spec")
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	if err := cron.Reschedule(id, "@hourly"); err != nil {
+		t.Fatal(err)
+	}
+	entry := cron.Entry(id)
+	if expected := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC); !entry.Next.Equal(expected) {
+		t.Errorf("expected next %v, got %v", expected, entry.Next)
+	}
+
+	if err := cron.ReplaceJob(id, FuncJob(func() { ran <- "new" })); err != nil {
+		t.Fatal(err)
+	}
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	select {
+	case job := <-ran:
+		if job != "new" {
+			t.Errorf("expected replaced job runs, got %s", job)
+		}
+	case <-time.After(OneSecond):
+		t.Fatal("expected job runs at the new schedule")
+	}
+
+	if err := cron.UpdateSchedule(id+1, Every(time.Minute)); err != ErrEntryNotFound {
+		t.Errorf("expected ErrEntryNotFound, got %v", err)
+	}
+	if err := cron.ReplaceJob(id+1, FuncJob(func() {})); err != ErrEntryNotFound {
+		t.Errorf("expected ErrEntryNotFound, got %v", err)
+	}
+}
+
+func TestRunNow(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, _ := newWithFakeClock(start)
+
+	ran := make(chan struct{}, 10)
+	release := make(chan struct{})
+	job := NewChain(SkipIfStillRunning(DiscardLogger)).Then(FuncJob(func() {
+		ran <- struct{}{}
+		<-release
+	}))
+	id, _ := cron.AddJob("@hourly", job)
+
+	// 在启动之前触发。
+	if err := cron.RunNow(id); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ran:
+	case <-time.After(OneSecond):
+		t.Fatal("expected job runs before start")
+	}
+
+	cron.Start()
+
+	// 前一次运行仍在进行中，因此链会跳过这次触发。
+	if err := cron.Trigger(id, true); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ran:
+		t.Error("expected SkipIfStillRunning to skip the triggered run")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if expected := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC); !cron.Entry(id).Next.Equal(expected) {
+		t.Errorf("expected next %v, got %v", expected, cron.Entry(id).Next)
+	}
+
+	// Stop 应该等待手动触发的作业。
+	ctx := cron.Stop()
+	select {
+	case <-ctx.Done():
+		t.Error("expected Stop to wait for the triggered job")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-ctx.Done():
+	case <-time.After(OneSecond):
+		t.Error("expected Stop context to be done after the job returned")
+	}
+
+	if err := cron.RunNow(id + 1); err != ErrEntryNotFound {
+		t.Errorf("expected ErrEntryNotFound, got %v", err)
+	}
+}
+
+func TestTriggerResetsNext(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+	id, _ := cron.AddFunc("@every 1h", func() {})
+	cron.Start()
+	defer cron.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(20 * time.Minute)
+	if err := cron.Trigger(id, true); err != nil {
+		t.Fatal(err)
+	}
+	if expected := start.Add(80 * time.Minute); !cron.Entry(id).Next.Equal(expected) {
+		t.Errorf("expected next %v, got %v", expected, cron.Entry(id).Next)
+	}
+}
+
+var benchmarkSizes = []int{1000, 10000, 100000}
+
+// newBenchmarkCron 返回一个在假时钟上运行的 Cron，其中有 n 个间隔不同的条目。
+func newBenchmarkCron(b *testing.B, n int) (*Cron, *FakeClock) {
+	b.Helper()
+	cron, clock := newWithFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), WithLogger(DiscardLogger))
+	for i := 0; i < n; i++ {
+		cron.Schedule(Every(time.Hour+time.Duration(i%3600)*time.Second), FuncJob(func() {}))
+	}
+	cron.Start()
+	clock.BlockUntil(1)
+	return cron, clock
+}
+
+func BenchmarkAddRemove(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			cron, _ := newBenchmarkCron(b, n)
+			defer cron.Stop()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				id := cron.Schedule(Every(time.Minute), FuncJob(func() {}))
+				cron.Remove(id)
+			}
+		})
+	}
+}
+
+func BenchmarkEntry(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			cron, _ := newBenchmarkCron(b, n)
+			defer cron.Stop()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cron.Entry(EntryID(i%n + 1))
+			}
+		})
+	}
+}
+
+func BenchmarkWake(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			cron, clock := newBenchmarkCron(b, n)
+			defer cron.Stop()
+			clock.Advance(time.Hour - time.Second)
+			clock.BlockUntil(1)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				clock.Advance(time.Second)
+				clock.BlockUntil(1)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DayRuleKind 是 DayRule 的种类。
+type DayRuleKind int
+
+const (
+	// LastDayOfMonth 匹配月末之前 N 天的日期，"L" 的 N 为 0，"L-3" 的 N 为 3。
+	LastDayOfMonth DayRuleKind = iota + 1
+	// LastWeekdayOfMonth 匹配月中最后一个星期 Weekday，例如 "5L" 表示最后一个星期五。
+	LastWeekdayOfMonth
+	// NearestWeekday 匹配离当月第 N 天最近的工作日（星期一到星期五），不会跨越月份，
+	// 例如 "15W"。N 为 0 时使用月末，即 "LW" 表示当月最后一个工作日。
+	NearestWeekday
+	// NthWeekdayOfMonth 匹配月中第 N 个星期 Weekday，例如 "MON#1" 表示第一个星期一。
+	NthWeekdayOfMonth
+)
+
+// DayRule 是相对于月份计算的日规则，用于表示无法用位集表达的日期，
+// 例如月末或月中最后一个星期五。
+type DayRule struct {
+	Kind DayRuleKind
+
+	// N 是规则的数值参数，含义取决于 Kind。
+	N int
+
+	// Weekday 是 LastWeekdayOfMonth 和 NthWeekdayOfMonth 规则匹配的星期几。
+	Weekday time.Weekday
+}
+
+// matches 如果给定时间所在的日期满足规则则返回 true。
+func (r DayRule) matches(t time.Time) bool {
+	last := daysIn(t.Month(), t.Year())
+	switch r.Kind {
+	case LastDayOfMonth:
+		return t.Day() == last-r.N
+	case LastWeekdayOfMonth:
+		return t.Weekday() == r.Weekday && t.Day()+7 > last
+	case NearestWeekday:
+		day := r.N
+		if day == 0 {
+			day = last
+		}
+		return day <= last && t.Day() == nearestWeekday(t, day, last)
+	case NthWeekdayOfMonth:
+		return t.Weekday() == r.Weekday && (t.Day()-1)/7+1 == r.N
+	}
+	return false
+}
+
+// nearestWeekday 返回 t 所在月份中离第 day 天最近的工作日，last 是该月的天数。
+// 如果最近的工作日在相邻的月份，则改为使用同一个月中另一侧的工作日。
+func nearestWeekday(t time.Time, day, last int) int {
+	switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+		return day + 1
+	}
+	return day
+}
+
+// daysIn 返回给定年份中给定月份的天数。
+func daysIn(m time.Month, year int) int {
+	return time.Date(year, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// matchesAny 如果给定时间满足任何一条规则则返回 true。
+func matchesAny(rules []DayRule, t time.Time) bool {
+	for _, r := range rules {
+		if r.matches(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// getDayField 类似于 getField，但还接受由 parseRule 识别的相对于月份的日规则。
+// 以规则表示的项目作为 DayRule 返回，其余项目仍然设置在位集中。
+func getDayField(field string, r bounds, parseRule func(expr string) (DayRule, bool, error)) (uint64, []DayRule, error) {
+	var (
+		bits  uint64
+		rules []DayRule
+	)
+	ranges := strings.FieldsFunc(field, func(r rune) bool { return r == ',' })
+	for _, expr := range ranges {
+		rule, ok, err := parseRule(expr)
+		if err != nil {
+			return bits, rules, err
+		}
+		if ok {
+			rules = append(rules, rule)
+			continue
+		}
+		bit, err := getRange(expr, r)
+		if err != nil {
+			return bits, rules, err
+		}
+		bits |= bit
+	}
+	return bits, rules, nil
+}
+
+// parseDomRule 将月中的日表达式解析为日规则：
+//
+//	"L" | "L-" number | number "W" | "LW"
+//
+// 如果表达式不是解析器启用的规则，ok 为 false。
+func (p Parser) parseDomRule(expr string) (rule DayRule, ok bool, err error) {
+	upper := strings.ToUpper(expr)
+	if p.options&WeekdayRules > 0 && len(expr) > 1 && strings.HasSuffix(upper, "W") {
+		if upper == "LW" {
+			return DayRule{Kind: NearestWeekday}, true, nil
+		}
+		day, err := mustParseInt(expr[:len(expr)-1])
+		if err != nil {
+			return DayRule{}, false, err
+		}
+		if day < dom.min || day > dom.max {
+			return DayRule{}, false, fmt.Errorf("day of month (%d) out of range (%d-%d): %s", day, dom.min, dom.max, expr)
+		}
+		return DayRule{Kind: NearestWeekday, N: int(day)}, true, nil
+	}
+	if p.options&LastDay == 0 {
+		return DayRule{}, false, nil
+	}
+	switch {
+	case upper == "L":
+		return DayRule{Kind: LastDayOfMonth}, true, nil
+	case strings.HasPrefix(upper, "L-"):
+		n, err := mustParseInt(expr[2:])
+		if err != nil {
+			return DayRule{}, false, err
+		}
+		if n >= dom.max {
+			return DayRule{}, false, fmt.Errorf("offset from last day (%d) above maximum (%d): %s", n, dom.max-1, expr)
+		}
+		return DayRule{Kind: LastDayOfMonth, N: int(n)}, true, nil
+	}
+	return DayRule{}, false, nil
+}
+
+// parseDowRule 将星期几表达式解析为日规则：
+//
+//	(number | name) "L" | (number | name) "#" number
+//
+// 如果表达式不是解析器启用的规则，ok 为 false。
+func (p Parser) parseDowRule(expr string) (rule DayRule, ok bool, err error) {
+	upper := strings.ToUpper(expr)
+	if p.options&WeekdayRules > 0 && strings.Contains(expr, "#") {
+		weekdayAndN := strings.Split(expr, "#")
+		if len(weekdayAndN) != 2 {
+			return DayRule{}, false, fmt.Errorf("too many hashes: %s", expr)
+		}
+		day, err := parseIntOrName(weekdayAndN[0], dow.names)
+		if err != nil {
+			return DayRule{}, false, err
+		}
+		if day > dow.max {
+			return DayRule{}, false, fmt.Errorf("day of week (%d) above maximum (%d): %s", day, dow.max, expr)
+		}
+		n, err := mustParseInt(weekdayAndN[1])
+		if err != nil {
+			return DayRule{}, false, err
+		}
+		if n < 1 || n > 5 {
+			return DayRule{}, false, fmt.Errorf("occurrence (%d) out of range (1-5): %s", n, expr)
+		}
+		return DayRule{Kind: NthWeekdayOfMonth, N: int(n), Weekday: time.Weekday(day)}, true, nil
+	}
+	if p.options&LastDay == 0 || len(expr) < 2 || !strings.HasSuffix(upper, "L") {
+		return DayRule{}, false, nil
+	}
+	day, err := parseIntOrName(expr[:len(expr)-1], dow.names)
+	if err != nil {
+		return DayRule{}, false, err
+	}
+	if day > dow.max {
+		return DayRule{}, false, fmt.Errorf("day of week (%d) above maximum (%d): %s", day, dow.max, expr)
+	}
+	return DayRule{Kind: LastWeekdayOfMonth, Weekday: time.Weekday(day)}, true, nil
+}
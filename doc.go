@@ -69,6 +69,13 @@ https://en.wikipedia.org/wiki/Cron
 这模拟了 Quartz，最流行的替代 Cron 调度格式：
 http://www.quartz-scheduler.org/documentation/quartz-2.x/tutorials/crontrigger.html
 
+Quartz 的年字段位于星期几之后，可以通过 Year（必需）或 YearOptional（可选）解析选项启用。
+年份的取值范围是 1970-2099，支持与其他字段相同的列表、范围和步长，例如 "2026-2030" 或 "2026/2"。
+指定了年份的调度在最后一个年份之后不再激活：
+
+	p := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.YearOptional)
+	p.Parse("0 0 9 1 1 ? 2027-2030")
+
 # 特殊字符
 
 星号 ( * )
@@ -96,6 +103,34 @@ http://www.quartz-scheduler.org/documentation/quartz-2.x/tutorials/crontrigger.h
 
 问号可以用来代替 '*' 来留空月中的日或星期几。
 
+L ( L )
+
+使用 LastDay 解析选项时，L 表示"最后"。在月中的日字段中，"L" 表示当月的最后一天
+（会考虑二月和闰年），"L-3" 表示最后一天之前的第3天。在星期几字段中，
+"5L" 或 "FRIL" 表示当月的最后一个星期五。它们可以与普通值组合，例如 "1,L"。
+
+	cron.New(cron.WithParser(cron.NewParser(
+		cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.LastDay)))
+
+W 和井号 ( W, # )
+
+使用 WeekdayRules 解析选项时，月中的日字段接受 "15W"，表示离当月15日最近的工作日
+（星期一到星期五）：如果15日是星期六则在14日运行，如果是星期日则在16日运行。
+最近的工作日不会跨越月份，因此 "1W" 在1日为星期六时于3日运行。"LW" 表示当月最后一个工作日。
+星期几字段接受 "MON#1" 或 "2#3" 这样的形式，表示当月第一个星期一或第三个星期二。
+
+哈希 ( H )
+
+当许多作业使用相同的规范（例如 "0 * * * *"）时，它们会在同一时刻运行。
+使用 Hash 解析选项时，任何字段都可以使用 "H" 代替具体的值，它由一个哈希种子确定：
+"H" 在字段的范围内选择一个值（月中的日限制为 1-28），"H(0-29)" 在给定范围内选择，
+"H/15" 从一个由种子确定的偏移量开始每隔15个单位运行一次。相同的种子总是得到相同的时间，
+因此作业的时间在重启后保持不变。Cron 使用条目的名称作为种子，也可以通过 ParseWithSeed 显式提供：
+
+	c := cron.New(cron.WithParser(cron.NewParser(
+		cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Hash)))
+	c.AddNamedFunc("cleanup", "H H(0-5) * * *", cleanup)
+
 # 预定义计划
 
 您可以使用几个预定义的计划之一来代替 cron 表达式。
@@ -124,6 +159,70 @@ http://www.quartz-scheduler.org/documentation/quartz-2.x/tutorials/crontrigger.h
 注意：间隔不考虑作业运行时间。例如，如果作业需要3分钟运行，
 并且计划每5分钟运行一次，它在每次运行之间只有2分钟的空闲时间。
 
+# 随机延迟
+
+为了避免许多实例中的相同条目在同一时刻访问共享的服务，使用 JitterSuffix 解析选项时
+可以在任何规范末尾加上 "~<duration>"，为每次激活加上一个不超过该时长的随机延迟：
+
+	c := cron.New(cron.WithParser(cron.NewParser(
+		cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor | cron.JitterSuffix)))
+	c.AddFunc("@every 1h ~5m", poll)
+	c.AddFunc("CRON_TZ=UTC 0 3 * * * ~30s", report)
+
+随机延迟不会累积，上面的第一个规范的每次激活都落在计划时间之后的五分钟之内。
+Jitter 调度装饰器提供相同的行为，JitterWithSource 可以指定随机数源以便在测试中复现延迟。
+与 WithLocker 一起使用时，锁的键使用加上延迟之前的时间，
+因此各副本即使抽取了不同的延迟，每次激活也只在一个副本上运行。
+
+# 一次性作业
+
+要在某个绝对时间只运行一次作业，请使用 AddOnce 或 At 调度，
+或者使用 RFC3339 格式的时间编写 cron 规范：
+
+	@at 2026-11-01T14:00:00Z
+
+一次性条目在运行后自动从 Cron 中删除。
+
+# 生命周期
+
+条目可以限制在一段时间内或一定的运行次数内：
+
+	c.AddFunc("@every 5m", poll,
+		cron.WithStartAt(launch), cron.WithEndAt(campaignEnd))
+	c.AddFunc("@every 1m", retry, cron.WithMaxRuns(3))
+
+Between 和 Limit 调度装饰器提供相同的限制，可以与 Schedule 一起使用。
+生命周期结束后条目被删除；Entry.RunCount 记录条目按计划运行的次数。
+
+# 错过的激活
+
+当调度器落后时（例如笔记本休眠、GC 暂停或长时间停止），定时器可能在激活时间
+之后很久才触发。默认情况下，每个到期的条目只运行一次，错过的激活被合并。
+可以使用 MisfirePolicy 改变这一行为，它借鉴了 Quartz 的 misfire 指令：
+
+	c := cron.New(cron.WithMisfirePolicy(cron.MisfirePolicy{
+		Mode:    cron.MisfireRunAll, // 或 MisfireRunOnce、MisfireSkip
+		Grace:   time.Minute,        // 延迟不超过一分钟的激活正常运行
+		MaxRuns: 10,                 // 最多补跑十次
+	}))
+
+单个条目可以在添加时使用 WithEntryMisfirePolicy 覆盖默认策略。
+
+# 持久化
+
+默认情况下条目只保存在内存中，因此重启后 @every 条目会重新开始计时，
+也无法知道 @daily 条目今天是否已经运行。使用 WithJobStore 安装 JobStore
+并用 WithName 为条目设置稳定的名称后，Cron 会在每次激活后保存条目的 Prev 和 Next，
+并在启动时从上次运行的时间继续计划：
+
+	store, err := cron.NewFileJobStore("/var/lib/myapp/cron.json")
+	...
+	c := cron.New(cron.WithJobStore(store))
+	c.AddFunc("@daily", report, cron.WithName("daily-report"))
+
+重启期间错过的激活会立即到期，并按照条目的 misfire 策略处理。
+状态在调度循环之外写入，Stop 返回的上下文会等待最新的状态写完。
+
 # 时区
 
 默认情况下，所有解释和调度都在机器的本地时区（time.Local）中完成。您可以在构造时指定不同的时区：
@@ -156,6 +255,41 @@ http://www.quartz-scheduler.org/documentation/quartz-2.x/tutorials/crontrigger.h
 
 请注意，在夏令时跳跃转换期间安排的作业将不会运行！
 
+# 上下文
+
+需要响应停止请求的长时间运行作业可以实现 JobContext 接口，
+或者使用 AddFuncCtx 提交函数。每次运行都会收到一个新的上下文，
+它在 Cron 停止时被取消；使用 WithJobTimeout 还可以为每次运行设置截止时间。
+
+	c.AddFuncCtx("@hourly", func(ctx context.Context) {
+		for !done() {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				step()
+			}
+		}
+	})
+
+StopWithContext 提供类似 http.Server 的 Shutdown 的优雅停止：它停止调度，
+在不取消作业上下文的情况下等待正在运行的作业完成；如果给定的上下文先结束，
+则取消仍在运行的作业的上下文，并返回一个列出这些条目的 *StopError：
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := c.StopWithContext(ctx); err != nil {
+		log.Print(err)
+	}
+
+需要报告失败的作业可以使用 AddFuncErr 或 FuncErrJob 提交返回错误的函数。
+返回的错误会沿着链向外传递，使包装器可以观察到它，最终通过 Logger.Error
+与条目 ID 一起记录：
+
+	c.AddFuncErr("@daily", func(ctx context.Context) error {
+		return backup(ctx)
+	})
+
 # 作业包装器
 
 Cron 运行器可以配置一系列作业包装器，为所有提交的作业添加横切功能。
@@ -164,6 +298,8 @@ Cron 运行器可以配置一系列作业包装器，为所有提交的作业添
   - 从作业中恢复任何恐慌（默认激活）
   - 如果前一次运行尚未完成，则延迟作业的执行
   - 如果前一次运行尚未完成，则跳过作业的执行
+  - 限制单次运行的时长，超时后取消或放弃作业
+  - 在同一次激活中以指数退避重试失败的作业
   - 记录每个作业的调用
   - 作业完成时的通知
 
@@ -181,9 +317,66 @@ Cron 运行器可以配置一系列作业包装器，为所有提交的作业添
 		cron.SkipIfStillRunning(cron.DefaultLogger),
 	).Then(job))
 
+自定义包装器应使用 `cron.RunWithContext` 调用被包装的作业，以便每次运行的上下文能够到达内层作业。
+
 作业包装器按照它们定义的顺序调用，因此 `Recover` 包装器通常应该最后出现
 （或者如果您希望恐慌对后续包装器可见，则在链的早期）。
 
+# 并发限制
+
+`LimitConcurrency` 包装器允许一个作业最多同时运行 n 次，超出的运行被跳过；
+`WithEntryMaxConcurrent` 为单个条目设置同样的上限。
+`WithMaxConcurrentJobs` 则限制所有条目同时运行的作业总数，
+QueuePolicy 决定工作槽用完时新的运行是等待、被丢弃，还是在有限的队列中等待：
+
+	c := cron.New(cron.WithMaxConcurrentJobs(4, cron.QueuePolicy{
+		Mode:      cron.QueueLimit,
+		MaxQueued: 16,
+	}))
+
+被丢弃的运行与被跳过的运行一样，以 ErrSkipped 报告给监听器。
+
+# 多副本
+
+当多个副本运行相同的条目时，使用 WithLocker 安装一个所有副本共享的 Locker，
+使每次激活只在获得锁的副本上运行。锁的键由条目名称和计划激活时间组成，
+因此各副本应使用 WithName 为条目设置相同的名称：
+
+	locker, err := cron.NewFileLocker("/var/lock/myapp-cron")
+	...
+	c := cron.New(cron.WithLocker(locker, 10*time.Minute))
+	c.AddFunc("@hourly", report, cron.WithName("hourly-report"))
+
+MemoryLocker 适用于同一进程中的多个 Cron；其他存储（例如 Redis 或数据库）
+可以通过实现 Locker 接口接入。作业可以使用 RunInfoFromContext 获取当前运行的条目和计划激活时间。
+
+另一种方式是使用 WithLeaderElector 在实例之间选举一个领导者：只有领导者运行作业，
+跟随者仍然计算条目的下一次激活时间，并在成为领导者后接替运行。
+通过 RunNow 或 Trigger 手动触发的运行不受选举限制，它们在被调用的实例上运行。
+LeaderElector 接口可以基于 etcd、Consul 或 Kubernetes 的租约实现；
+FileElector 提供了一个基于本地租约文件的实现。实现了 LeadershipListener 的监听器
+会在领导权变化时得到通知。
+
+# 事件监听
+
+除了日志之外，Cron 还可以向实现了 Listener 接口的监听器报告类型化的事件：
+调度器的启动和停止、条目的添加和删除、作业的开始和结束（包括运行时长、
+返回的错误和 panic），以及错过的激活。使用 WithListener 注册监听器，
+并嵌入 NopListener 以只实现感兴趣的方法：
+
+	type alerts struct{ cron.NopListener }
+
+	func (alerts) OnJobEnd(ev cron.JobEvent) {
+		if ev.Err != nil {
+			page(ev.Entry.Name, ev.Err)
+		}
+	}
+
+	c := cron.New(cron.WithListener(alerts{}))
+
+子包 metrics 提供了一个基于 Listener 的 Collector，它以 Prometheus 文本格式
+通过 http.Handler 导出作业运行、失败、panic、跳过、运行时长和调度延迟等指标。
+
 # 线程安全
 
 由于 Cron 服务与调用代码并发运行，必须采取一定的注意措施来确保正确的同步。
@@ -207,12 +400,13 @@ Cron 默认不需要或提供任何日志记录。如上所述启用它或根据
 
 # 实现
 
-Cron 条目存储在一个数组中，按其下次激活时间排序。Cron 睡眠直到下一个作业应该运行。
+Cron 条目存储在一个按下次激活时间排列的最小堆中，并通过 ID 索引查找。
+Cron 睡眠直到下一个作业应该运行。
 
 唤醒时：
   - 它运行在该秒钟活跃的每个条目
   - 它计算已运行作业的下次运行时间
-  - 它按下次激活时间重新排序条目数组
+  - 它在堆中调整这些条目的位置
   - 它睡眠直到最早的作业
 */
 package cron
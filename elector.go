@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// LeaderElector 在运行相同条目的多个 Cron 实例之间选举一个领导者。
+// 使用 WithLeaderElector 安装后，只有领导者按计划运行作业；跟随者仍然计算每个条目的
+// 下一次激活时间并记录 Prev 和 RunCount，因此 Entries 在所有实例上都保持最新，但不会启动作业。
+// 通过 RunNow 或 Trigger 手动触发的运行是例外：它们在被调用的实例上运行，无论它是否是领导者。
+//
+// 实现可以基于 etcd、Consul 或 Kubernetes 的租约等服务，
+// FileElector 提供了一个基于本地租约文件的实现。
+type LeaderElector interface {
+	// Elect 参与选举，直到 ctx 结束才返回。每当本实例获得或失去领导权时，
+	// 实现调用 notify 报告新的状态。返回时本实例不再是领导者，实现应该释放它持有的租约。
+	//
+	// 与后端的临时通信错误应该在 Elect 内部重试；如果 Elect 在 ctx 结束之前返回错误，
+	// Cron 记录错误，并在本次运行期间保持为跟随者。
+	Elect(ctx context.Context, notify func(leader bool)) error
+}
+
+// LeadershipListener 可以由 Listener 额外实现，以在本实例获得或失去领导权时得到通知。
+type LeadershipListener interface {
+	// OnLeadershipChange 在本实例的领导权发生变化时调用。
+	OnLeadershipChange(leader bool)
+}
+
+// elect 在自己的 goroutine 中参与选举，直到 ctx 结束。
+// Stop 返回的上下文会等待选举结束，使租约在进程退出之前被释放。
+//
+// 停止后立即重新启动时，新的选举等待上一次选举结束才开始，
+// 使上一次选举释放租约和放弃领导权不会影响新的选举。
+func (c *Cron) elect(ctx context.Context) {
+	prev := c.elected
+	done := make(chan struct{})
+	c.elected = done
+	c.jobWaiter.Add(1)
+	go func() {
+		defer c.jobWaiter.Done()
+		defer close(done)
+		if prev != nil {
+			<-prev
+		}
+		if err := c.elector.Elect(ctx, c.setLeader); err != nil && ctx.Err() == nil {
+			c.logger.Error(err, "leader election failed")
+		}
+		c.setLeader(false)
+	}()
+}
+
+// setLeader 记录本实例的领导权，并在它发生变化时通知监听器。
+func (c *Cron) setLeader(leader bool) {
+	var v int32
+	if leader {
+		v = 1
+	}
+	if atomic.SwapInt32(&c.leader, v) == v {
+		return
+	}
+	c.logger.Info("leadership", "leader", leader)
+	for _, l := range c.listeners {
+		if ll, ok := l.(LeadershipListener); ok {
+			ll.OnLeadershipChange(leader)
+		}
+	}
+}
+
+// isLeader 报告本实例是否应该运行作业。没有配置 LeaderElector 时总是 true。
+func (c *Cron) isLeader() bool {
+	return c.elector == nil || atomic.LoadInt32(&c.leader) == 1
+}
@@ -0,0 +1,102 @@
+package cron
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// manualElector 是由测试控制领导权的 LeaderElector。
+type manualElector struct {
+	notify chan func(bool)
+}
+
+func (e *manualElector) Elect(ctx context.Context, notify func(bool)) error {
+	e.notify <- notify
+	<-ctx.Done()
+	return nil
+}
+
+type leadershipRecorder struct {
+	NopListener
+	mu      sync.Mutex
+	changes []bool
+}
+
+func (l *leadershipRecorder) OnLeadershipChange(leader bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.changes = append(l.changes, leader)
+}
+
+func TestLeaderElector(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	elector := &manualElector{notify: make(chan func(bool))}
+	var listener leadershipRecorder
+	cron, clock := newWithFakeClock(start,
+		WithLeaderElector(elector), WithListener(&listener))
+
+	var runs int64
+	id, _ := cron.AddFunc("@hourly", func() { atomic.AddInt64(&runs, 1) })
+	limited, _ := cron.AddFunc("@hourly", func() { atomic.AddInt64(&runs, 1) }, WithMaxRuns(1))
+	cron.Start()
+	notify := <-elector.notify
+
+	// 作为跟随者，作业不会运行，但下一次激活时间仍然前进。
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	clock.BlockUntil(1)
+	entry := cron.Entry(id)
+	if !entry.Next.Equal(time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected a follower to keep computing next, got %v", entry.Next)
+	}
+	if !entry.Prev.Equal(time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)) || entry.RunCount != 1 {
+		t.Errorf("expected a follower to record the activation, got prev %v and %d runs", entry.Prev, entry.RunCount)
+	}
+
+	notify(true)
+	clock.Advance(time.Hour)
+	clock.BlockUntil(1)
+	if cron.Entry(limited).Valid() {
+		t.Error("expected an entry whose runs were used up as a follower to be removed")
+	}
+	<-cron.Stop().Done()
+
+	if runs != 1 {
+		t.Errorf("expected only the leader's activation to run, got %d runs", runs)
+	}
+	if expected := []bool{true, false}; !reflect.DeepEqual(listener.changes, expected) {
+		t.Errorf("expected leadership changes %v, got %v", expected, listener.changes)
+	}
+}
+
+// 测试停止后立即重新启动时，上一次选举结束时放弃领导权不会覆盖新选举的结果。
+func TestLeaderElectorRestart(t *testing.T) {
+	elector := &manualElector{notify: make(chan func(bool))}
+	var listener leadershipRecorder
+	cron := New(WithLeaderElector(elector), WithListener(&listener))
+
+	for i := 0; i < 10; i++ {
+		cron.Start()
+		notify := <-elector.notify
+		notify(true)
+		cron.Stop()
+	}
+	cron.Start()
+	(<-elector.notify)(true)
+	if !cron.isLeader() {
+		t.Error("expected the restarted scheduler to be the leader")
+	}
+	<-cron.Stop().Done()
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	for i, leader := range listener.changes {
+		if leader != (i%2 == 0) {
+			t.Fatalf("expected leadership changes to alternate, got %v", listener.changes)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package cron
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileElector 是使用本地目录中的租约文件实现的 LeaderElector，主要用于本地测试。
+// 租约文件记录当前领导者的标识和租约的过期时间，领导者每隔 TTL 的三分之一续约一次；
+// 领导者崩溃后，其他实例在租约过期时接替它。与 FileLocker 一样，
+// 对租约文件的访问由目录中 .lock 文件上的 flock 保护。
+type FileElector struct {
+	locker *FileLocker
+	name   string
+	ttl    time.Duration
+	id     string
+}
+
+// NewFileElector 返回一个在 dir 中为名为 name 的选举保存租约的 FileElector，
+// 必要时创建该目录。参与同一选举的实例应使用相同的 dir 和 name。
+// ttl 太短而无法续约时返回错误。
+func NewFileElector(dir, name string, ttl time.Duration) (*FileElector, error) {
+	if ttl/3 <= 0 {
+		return nil, fmt.Errorf("file elector ttl too short: %v", ttl)
+	}
+	locker, err := NewFileLocker(dir)
+	if err != nil {
+		return nil, err
+	}
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	id := fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b[:]))
+	return &FileElector{locker: locker, name: name, ttl: ttl, id: id}, nil
+}
+
+// Elect 实现 LeaderElector。
+func (e *FileElector) Elect(ctx context.Context, notify func(leader bool)) error {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	leader := false
+	for {
+		ok, err := e.acquire()
+		if err != nil {
+			// 无法确认租约时不能继续作为领导者。
+			ok = false
+		}
+		if ok != leader {
+			leader = ok
+			notify(leader)
+		}
+
+		select {
+		case <-ctx.Done():
+			if leader {
+				notify(false)
+				return e.release()
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// acquire 获取或续约租约，如果本实例是领导者则返回 true。
+func (e *FileElector) acquire() (bool, error) {
+	unlock, err := e.locker.lockDir()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	now := e.locker.now()
+	if owner, expiry, ok := e.read(); ok && owner != e.id && expiry.After(now) {
+		return false, nil
+	}
+	data := e.id + "\n" + now.Add(e.ttl).Format(time.RFC3339Nano)
+	if err := os.WriteFile(e.path(), []byte(data), 0o644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// release 在本实例持有租约时删除它，使其他实例可以立即接替。
+func (e *FileElector) release() error {
+	unlock, err := e.locker.lockDir()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if owner, _, ok := e.read(); !ok || owner != e.id {
+		return nil
+	}
+	if err := os.Remove(e.path()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// read 读取租约文件中的领导者标识和过期时间。如果文件不存在或无法解析，ok 为 false。
+func (e *FileElector) read() (owner string, expiry time.Time, ok bool) {
+	data, err := os.ReadFile(e.path())
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	owner, value, found := strings.Cut(string(data), "\n")
+	if !found {
+		return "", time.Time{}, false
+	}
+	expiry, err = time.Parse(time.RFC3339Nano, value)
+	return owner, expiry, err == nil
+}
+
+// path 返回租约文件的路径。
+func (e *FileElector) path() string {
+	return filepath.Join(e.locker.dir, url.PathEscape(e.name)+".leader")
+}
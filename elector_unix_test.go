@@ -0,0 +1,108 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFileElector(t *testing.T) {
+	dir := t.TempDir()
+	const ttl = 30 * time.Millisecond
+
+	type candidate struct {
+		cancel  context.CancelFunc
+		changes chan bool
+		done    chan struct{}
+	}
+	elect := func() *candidate {
+		elector, err := NewFileElector(dir, "scheduler", ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		c := &candidate{cancel, make(chan bool, 10), make(chan struct{})}
+		go func() {
+			defer close(c.done)
+			elector.Elect(ctx, func(leader bool) { c.changes <- leader })
+		}()
+		return c
+	}
+	expect := func(c *candidate, leader bool) {
+		t.Helper()
+		select {
+		case got := <-c.changes:
+			if got != leader {
+				t.Fatalf("expected leader=%v, got %v", leader, got)
+			}
+		case <-time.After(OneSecond):
+			t.Fatalf("timed out waiting for leader=%v", leader)
+		}
+	}
+
+	first := elect()
+	expect(first, true)
+	second := elect()
+	select {
+	case <-second.changes:
+		t.Fatal("expected the second candidate to follow while the lease is held")
+	case <-time.After(3 * ttl):
+	}
+
+	// 领导者退出时释放租约，跟随者在下一次尝试时接替它。
+	first.cancel()
+	expect(first, false)
+	<-first.done
+	expect(second, true)
+
+	second.cancel()
+	expect(second, false)
+	<-second.done
+}
+
+// 测试停止后立即重新启动时，上一次选举释放租约不会删除新选举获得的租约。
+func TestFileElectorRestart(t *testing.T) {
+	const ttl = 30 * time.Millisecond
+	elector, err := NewFileElector(t.TempDir(), "scheduler", ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cron := New(WithLeaderElector(elector), WithLogger(DiscardLogger))
+	waitLeader := func() {
+		t.Helper()
+		deadline := time.Now().Add(OneSecond)
+		for !cron.isLeader() {
+			if time.Now().After(deadline) {
+				t.Fatal("timed out waiting for leadership")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		cron.Start()
+		waitLeader()
+		cron.Stop()
+	}
+	cron.Start()
+	waitLeader()
+	time.Sleep(2 * ttl)
+	unlock, err := elector.locker.lockDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, _, ok := elector.read()
+	unlock()
+	if !cron.isLeader() || !ok || owner != elector.id {
+		t.Errorf("expected the restarted scheduler to hold the lease, got leader=%v owner=%q", cron.isLeader(), owner)
+	}
+	<-cron.Stop().Done()
+}
+
+func TestFileElectorShortTTL(t *testing.T) {
+	if _, err := NewFileElector(t.TempDir(), "scheduler", 2*time.Nanosecond); err == nil {
+		t.Error("expected an error for a ttl too short to renew")
+	}
+}
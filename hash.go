@@ -0,0 +1,111 @@
+package cron
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// SeededScheduleParser 是可以使用哈希种子解析 "H" 字段的 ScheduleParser。
+// Cron 为命名条目使用条目名称作为种子，因此同一规范在不同条目中得到不同但稳定的时间。
+type SeededScheduleParser interface {
+	ScheduleParser
+	ParseWithSeed(spec, seed string) (Schedule, error)
+}
+
+// fieldBounds 是完整字段集合中各个字段的边界。
+var fieldBounds = []bounds{seconds, minutes, hours, dom, months, dow}
+
+// hashBounds 是各个字段中不带范围的 "H" 取值的范围。月中的日限制为 1-28，
+// 使哈希得到的日期在每个月都存在。
+var hashBounds = []bounds{seconds, minutes, hours, {1, 28, nil}, months, dow}
+
+// expandHash 将完整字段集合中的 "H" 项目替换为由种子确定的具体值：
+//
+//	"H" [ "(" number "-" number ")" ] [ "/" number ]
+//
+// "H" 在字段的范围内选择一个值，"H(0-29)" 在给定范围内选择，
+// "H/15" 在步长内选择一个偏移量，并从该偏移量开始每隔15个单位激活一次。
+// 同一个种子总是得到相同的值，而不同字段使用不同的值。
+func expandHash(fields []string, seed string) ([]string, error) {
+	expanded := make([]string, len(fields))
+	for i, field := range fields {
+		ranges := strings.Split(field, ",")
+		for j, expr := range ranges {
+			if !strings.HasPrefix(strings.ToUpper(expr), "H") {
+				continue
+			}
+			if seed == "" {
+				return nil, fmt.Errorf("hash key required for %s: use ParseWithSeed or a named entry", expr)
+			}
+			value, err := hashExpr(expr, fieldBounds[i], hashBounds[i], hashValue(seed, i))
+			if err != nil {
+				return nil, err
+			}
+			ranges[j] = value
+		}
+		expanded[i] = strings.Join(ranges, ",")
+	}
+	return expanded, nil
+}
+
+// hashExpr 将单个 "H" 项目展开为等价的普通范围表达式。
+// 显式的范围必须在字段的边界 r 之内，没有范围时使用 def。
+func hashExpr(expr string, r, def bounds, hash uint64) (string, error) {
+	rangeAndStep := strings.Split(expr[1:], "/")
+	if len(rangeAndStep) > 2 {
+		return "", fmt.Errorf("too many slashes: %s", expr)
+	}
+
+	start, end := def.min, def.max
+	if rng := rangeAndStep[0]; rng != "" {
+		if !strings.HasPrefix(rng, "(") || !strings.HasSuffix(rng, ")") {
+			return "", fmt.Errorf("failed to parse hash range from %s", expr)
+		}
+		lowAndHigh := strings.Split(rng[1:len(rng)-1], "-")
+		if len(lowAndHigh) != 2 {
+			return "", fmt.Errorf("failed to parse hash range from %s", expr)
+		}
+		var err error
+		if start, err = mustParseInt(lowAndHigh[0]); err != nil {
+			return "", err
+		}
+		if end, err = mustParseInt(lowAndHigh[1]); err != nil {
+			return "", err
+		}
+		if start < r.min {
+			return "", fmt.Errorf("beginning of range (%d) below minimum (%d): %s", start, r.min, expr)
+		}
+		if end > r.max {
+			return "", fmt.Errorf("end of range (%d) above maximum (%d): %s", end, r.max, expr)
+		}
+		if start > end {
+			return "", fmt.Errorf("beginning of range (%d) beyond end of range (%d): %s", start, end, expr)
+		}
+	}
+
+	if len(rangeAndStep) == 1 {
+		return strconv.FormatUint(uint64(start)+hash%uint64(end-start+1), 10), nil
+	}
+	step, err := mustParseInt(rangeAndStep[1])
+	if err != nil {
+		return "", err
+	}
+	if step == 0 {
+		return "", fmt.Errorf("step of range should be a positive number: %s", expr)
+	}
+	offset := uint(hash % uint64(step))
+	if start+offset > end {
+		offset = 0
+	}
+	return fmt.Sprintf("%d-%d/%d", start+offset, end, step), nil
+}
+
+// hashValue 返回种子在第 field 个字段中的哈希值。
+func hashValue(seed string, field int) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	h.Write([]byte{0, byte(field)})
+	return h.Sum64()
+}
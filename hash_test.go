@@ -0,0 +1,123 @@
+package cron
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var hashParser = NewParser(Minute | Hour | Dom | Month | Dow | Hash)
+
+func TestParseHash(t *testing.T) {
+	parse := func(spec, seed string) *SpecSchedule {
+		t.Helper()
+		sched, err := hashParser.ParseWithSeed(spec, seed)
+		if err != nil {
+			t.Fatalf("%s => unexpected error %v", spec, err)
+		}
+		return sched.(*SpecSchedule)
+	}
+
+	// 相同的种子总是得到相同的值，不同的种子分散在整个范围内。
+	minutesSeen := make(map[uint64]bool)
+	for i := 0; i < 100; i++ {
+		seed := fmt.Sprint("job-", i)
+		a, b := parse("H * * * *", seed), parse("H * * * *", seed)
+		if a.Minute != b.Minute {
+			t.Fatalf("expected a stable minute for %s, got %b and %b", seed, a.Minute, b.Minute)
+		}
+		minutesSeen[a.Minute] = true
+	}
+	if len(minutesSeen) < 30 {
+		t.Errorf("expected hashed minutes to be spread out, got %d distinct values", len(minutesSeen))
+	}
+
+	for i := 0; i < 100; i++ {
+		seed := fmt.Sprint("job-", i)
+		if s := parse("H(0-29) H * * *", seed); s.Minute&getBits(0, 29, 1) == 0 || s.Minute&^getBits(0, 29, 1) != 0 {
+			t.Errorf("%s: expected a single minute within 0-29, got %b", seed, s.Minute)
+		}
+		if s := parse("0 0 H * *", seed); s.Dom&^getBits(1, 28, 1) != 0 {
+			t.Errorf("%s: expected a day of month within 1-28, got %b", seed, s.Dom)
+		}
+		s := parse("H/15 * * * *", seed)
+		var offset uint
+		for s.Minute&(1<<offset) == 0 {
+			offset++
+		}
+		if offset >= 15 || s.Minute != getBits(offset, 59, 15) {
+			t.Errorf("%s: expected every 15 minutes from an offset below 15, got %b", seed, s.Minute)
+		}
+	}
+
+	if a, b := parse("H H * * *", "job"), parse("H H * * *", "job"); a.Hour != b.Hour {
+		t.Error("expected a stable hour")
+	}
+}
+
+func TestParseHashErrors(t *testing.T) {
+	var tests = []struct {
+		parser          Parser
+		expr, seed, err string
+	}{
+		{standardParser, "H * * * *", "job", "failed to parse int from"},
+		{hashParser, "H * * * *", "", "hash key required"},
+		{hashParser, "H(0-x) * * * *", "job", "failed to parse int from"},
+		{hashParser, "H(30-10) * * * *", "job", "beyond end of range"},
+		{hashParser, "H(0-70) * * * *", "job", "above maximum"},
+		{hashParser, "0 0 H(0-10) * *", "job", "below minimum"},
+		{hashParser, "H[0-29] * * * *", "job", "failed to parse hash range"},
+		{hashParser, "H/0 * * * *", "job", "should be a positive number"},
+		{hashParser, "H/5/5 * * * *", "job", "too many slashes"},
+	}
+	for _, c := range tests {
+		_, err := c.parser.ParseWithSeed(c.expr, c.seed)
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s => expected %v, got %v", c.expr, c.err, err)
+		}
+	}
+}
+
+func TestNamedEntriesUseHashSeed(t *testing.T) {
+	cron := New(WithParser(hashParser), WithLocation(time.UTC))
+	first, err := cron.AddNamedFunc("first", "H H * * *", func() {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := cron.AddFunc("H H * * *", func() {}, WithName("second"))
+	if _, err := cron.AddFunc("H H * * *", func() {}); err == nil {
+		t.Error("expected an error for an unnamed entry with a hashed spec")
+	}
+
+	expected, _ := hashParser.ParseWithSeed("H H * * *", "first")
+	if actual := cron.Entry(first).Schedule; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the entry name to seed the schedule, got %v", actual)
+	}
+	expected, _ = hashParser.ParseWithSeed("H H * * *", "second")
+	if actual := cron.Entry(second).Schedule; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected WithName to seed the schedule, got %v", actual)
+	}
+
+	if err := cron.Reschedule(first, "H/30 * * * *"); err != nil {
+		t.Fatal(err)
+	}
+	expected, _ = hashParser.ParseWithSeed("H/30 * * * *", "first")
+	if actual := cron.Entry(first).Schedule; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected Reschedule to keep the seed, got %v", actual)
+	}
+}
+
+// 测试 AddJob 只应用每个条目选项一次。
+func TestAddJobAppliesOptionsOnce(t *testing.T) {
+	cron := New(WithParser(hashParser), WithLocation(time.UTC))
+	var applied int
+	count := func(*Entry) { applied++ }
+	if _, err := cron.AddFunc("H H * * *", func() {}, WithName("count"), count); err != nil {
+		t.Fatal(err)
+	}
+	if applied != 1 {
+		t.Errorf("expected the option to be applied once, got %d", applied)
+	}
+}
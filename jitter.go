@@ -0,0 +1,100 @@
+package cron
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-utils2/time2"
+)
+
+// JitterSchedule 为另一个调度的每次激活加上一个 0 到 MaxDelay 之间的随机延迟，
+// 使许多 Cron 实例中的相同条目不会在同一时刻访问共享的服务。
+// MaxDelay 不小于一秒时，延迟是整数秒。
+//
+// 随机延迟不会累积：在一次激活之后计算下一次激活时，JitterSchedule 从上一次
+// 未加延迟的时间继续计算，因此 "@every 1h ~5m" 的激活始终落在整点之后的五分钟之内。
+// 在激活到来之前重新计算 Next 会返回相同的时间。JitterSchedule 是有状态的，不应在条目之间共享。
+type JitterSchedule struct {
+	MaxDelay time.Duration
+	Schedule Schedule
+
+	mu   sync.Mutex
+	rand *rand.Rand
+	from time.Time // 计算当前激活时使用的时间
+	base time.Time // 当前激活未加延迟的时间
+	last time.Time // 当前激活的时间
+}
+
+// Jitter 返回一个为每次激活加上最多 maxDelay 随机延迟的调度。
+func Jitter(maxDelay time.Duration, schedule Schedule) *JitterSchedule {
+	return JitterWithSource(maxDelay, schedule, rand.NewSource(time2.Now().UnixNano()))
+}
+
+// JitterWithSource 类似于 Jitter，但使用给定的随机数源，
+// 从而可以在测试中复现延迟。JitterSchedule 在使用 source 时持有锁，
+// 因此 source 不需要是并发安全的，但也不应该在其他地方使用。
+func JitterWithSource(maxDelay time.Duration, schedule Schedule, source rand.Source) *JitterSchedule {
+	return &JitterSchedule{MaxDelay: maxDelay, Schedule: schedule, rand: rand.New(source)}
+}
+
+// Next 返回被包装调度在 t 之后的下一次激活时间加上随机延迟。
+func (schedule *JitterSchedule) Next(t time.Time) time.Time {
+	schedule.mu.Lock()
+	defer schedule.mu.Unlock()
+
+	if !schedule.last.IsZero() && t.Before(schedule.last) && !t.Before(schedule.from) {
+		// 当前激活还没有到来。
+		return schedule.last
+	}
+
+	var base time.Time
+	if !schedule.last.IsZero() && !t.Before(schedule.last) {
+		// 当前激活已经到来，从它未加延迟的时间继续计算。
+		base = schedule.Schedule.Next(schedule.base)
+	}
+	if base.IsZero() || !base.After(t) {
+		base = schedule.Schedule.Next(t)
+	}
+	if base.IsZero() {
+		schedule.from, schedule.base, schedule.last = time.Time{}, time.Time{}, time.Time{}
+		return time.Time{}
+	}
+
+	schedule.from = t
+	schedule.base = base
+	schedule.last = base.Add(schedule.delay())
+	return schedule.last
+}
+
+// activation 如果 t 是当前激活的时间，返回它未加延迟的时间；否则返回 t。
+// 各副本为同一次激活抽取不同的延迟，但未加延迟的时间相同，因此它被用作 RunInfo.Activation。
+func (schedule *JitterSchedule) activation(t time.Time) time.Time {
+	schedule.mu.Lock()
+	defer schedule.mu.Unlock()
+	if !schedule.last.IsZero() && t.Equal(schedule.last) {
+		return schedule.base
+	}
+	return t
+}
+
+func (schedule *JitterSchedule) bounded() bool { return isBounded(schedule.Schedule) }
+
+func (schedule *JitterSchedule) onceTime() (time.Time, bool) { return onceTimeOf(schedule.Schedule) }
+
+// activationSchedule 由推迟激活时间的调度实现（例如 JitterSchedule），
+// 它返回激活时间 t 在推迟之前的时间。
+type activationSchedule interface {
+	activation(t time.Time) time.Time
+}
+
+// delay 返回一个 0 到 MaxDelay 之间的随机延迟。
+func (schedule *JitterSchedule) delay() time.Duration {
+	if schedule.MaxDelay <= 0 {
+		return 0
+	}
+	if schedule.MaxDelay < time.Second {
+		return time.Duration(schedule.rand.Int63n(int64(schedule.MaxDelay) + 1))
+	}
+	return time.Duration(schedule.rand.Int63n(int64(schedule.MaxDelay/time.Second)+1)) * time.Second
+}
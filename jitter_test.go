@@ -0,0 +1,123 @@
+package cron
+
+import (
+	"math/rand"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJitterScheduleNext(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule := JitterWithSource(5*time.Minute, Every(time.Hour), rand.NewSource(1))
+
+	prev := start
+	for i := 1; i <= 100; i++ {
+		next := schedule.Next(prev)
+		// 每次激活都在整点之后五分钟之内，延迟不会累积。
+		base := start.Add(time.Duration(i) * time.Hour)
+		if next.Before(base) || next.After(base.Add(5*time.Minute)) {
+			t.Fatalf("activation %d: expected between %v and %v, got %v", i, base, base.Add(5*time.Minute), next)
+		}
+		if next.Nanosecond() != 0 {
+			t.Fatalf("activation %d: expected whole seconds, got %v", i, next)
+		}
+		// 在激活到来之前重新计算会返回相同的时间。
+		if again := schedule.Next(prev); !again.Equal(next) {
+			t.Fatalf("activation %d: expected %v on recompute, got %v", i, next, again)
+		}
+		prev = next
+	}
+}
+
+func TestJitterScheduleReproducible(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := JitterWithSource(time.Minute, Every(time.Hour), rand.NewSource(42))
+	b := JitterWithSource(time.Minute, Every(time.Hour), rand.NewSource(42))
+
+	prevA, prevB := start, start
+	for i := 0; i < 10; i++ {
+		prevA, prevB = a.Next(prevA), b.Next(prevB)
+		if !prevA.Equal(prevB) {
+			t.Fatalf("activation %d: expected %v, got %v", i, prevA, prevB)
+		}
+	}
+}
+
+func TestJitterScheduleEnd(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule := JitterWithSource(time.Minute, At(at), rand.NewSource(1))
+
+	next := schedule.Next(at.Add(-time.Hour))
+	if next.Before(at) || next.After(at.Add(time.Minute)) {
+		t.Fatalf("expected between %v and %v, got %v", at, at.Add(time.Minute), next)
+	}
+	if next = schedule.Next(next); !next.IsZero() {
+		t.Errorf("expected zero time after the last activation, got %v", next)
+	}
+}
+
+// 测试从 JobStore 恢复的带随机延迟的条目每次激活只运行一次。
+func TestJitterRestoredRunsOnce(t *testing.T) {
+	hourly, _ := ParseStandard("0 * * * *")
+	prev := time.Date(2026, 1, 1, 10, 2, 0, 0, time.UTC)
+	for seed := int64(0); seed < 20; seed++ {
+		store := NewMemoryJobStore()
+		store.Save(EntryState{Name: "report", Prev: prev})
+		cron, clock := newWithFakeClock(prev.Add(28*time.Minute), WithJobStore(store))
+
+		var runs int64
+		schedule := JitterWithSource(5*time.Minute, hourly, rand.NewSource(seed))
+		cron.Schedule(schedule, FuncJob(func() { atomic.AddInt64(&runs, 1) }), WithName("report"))
+		cron.Start()
+
+		for i := 0; i < 40; i++ {
+			clock.BlockUntil(1)
+			clock.Advance(time.Minute)
+		}
+		clock.BlockUntil(1)
+		<-cron.Stop().Done()
+		if runs != 1 {
+			t.Errorf("seed %d: expected the 11:00 activation to run once, got %d runs", seed, runs)
+		}
+	}
+}
+
+var jitterParser = NewParser(Minute | Hour | Dom | Month | Dow | Descriptor | JitterSuffix)
+
+func TestParseJitter(t *testing.T) {
+	sched, err := jitterParser.Parse("@every 1h ~5m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jitter, ok := sched.(*JitterSchedule)
+	if !ok {
+		t.Fatalf("expected *JitterSchedule, got %T", sched)
+	}
+	if jitter.MaxDelay != 5*time.Minute {
+		t.Errorf("expected max delay 5m, got %v", jitter.MaxDelay)
+	}
+	if expected := Every(time.Hour); !reflect.DeepEqual(jitter.Schedule, expected) {
+		t.Errorf("expected %v, got %v", expected, jitter.Schedule)
+	}
+
+	sched, err = jitterParser.Parse("CRON_TZ=UTC 0 5 * * * ~30s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec, ok := sched.(*JitterSchedule).Schedule.(*SpecSchedule); !ok || spec.Location != time.UTC {
+		t.Errorf("expected spec schedule in UTC, got %v", sched.(*JitterSchedule).Schedule)
+	}
+
+	for _, spec := range []string{"@every 1h ~", "@every 1h ~5", "0 5 * * * ~abc", "0 5 * * ~5m", "@every 1h ~-5m"} {
+		if _, err := jitterParser.Parse(spec); err == nil {
+			t.Errorf("%s: expected an error", spec)
+		}
+	}
+
+	// 没有 JitterSuffix 选项的解析器不接受随机延迟。
+	if _, err := ParseStandard("0 5 * * * ~30s"); err == nil {
+		t.Error("expected the standard parser to reject a jitter suffix")
+	}
+}
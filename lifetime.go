@@ -0,0 +1,116 @@
+package cron
+
+import (
+	"sync"
+	"time"
+)
+
+// BetweenSchedule 将另一个调度的激活限制在 [Start, End] 时间范围内，
+// 例如"每5分钟，但只在活动开始和结束之间"。零值的 Start 或 End 表示该方向没有限制。
+type BetweenSchedule struct {
+	Start, End time.Time
+	Schedule   Schedule
+}
+
+// Between 返回一个只在 start 和 end 之间（包括两端）激活的调度。
+func Between(start, end time.Time, schedule Schedule) BetweenSchedule {
+	return BetweenSchedule{Start: start, End: end, Schedule: schedule}
+}
+
+// Next 返回被包装调度在时间范围内的下一次激活时间。
+// 如果下一次激活晚于 End，则返回零时间。
+func (schedule BetweenSchedule) Next(t time.Time) time.Time {
+	return nextBetween(schedule.Start, schedule.End, schedule.Schedule, t)
+}
+
+// nextBetween 返回 schedule 在 t 之后、不早于 start 且不晚于 end 的下一次激活时间。
+func nextBetween(start, end time.Time, schedule Schedule, t time.Time) time.Time {
+	if !start.IsZero() && t.Before(start) {
+		// 从 start 之前的一刻开始计算，使恰好在 start 的激活也能被包含。
+		t = start.Add(-time.Nanosecond)
+	}
+	next := schedule.Next(t)
+	if next.IsZero() || (!end.IsZero() && next.After(end)) {
+		return time.Time{}
+	}
+	return next
+}
+
+// LimitSchedule 将另一个调度的激活次数限制为 N 次，例如"重试三次然后停止"。
+//
+// 当 Next 被要求计算不早于上一次返回的激活时间之后的时间时，
+// 该激活被视为已消耗。因此在激活到来之前重新计算 Next（例如条目被添加或
+// 重新调度时）不会消耗次数。LimitSchedule 是有状态的，不应在条目之间共享。
+type LimitSchedule struct {
+	N        int
+	Schedule Schedule
+
+	mu   sync.Mutex
+	used int
+	last time.Time
+}
+
+// Limit 返回一个最多激活 n 次的调度。
+func Limit(n int, schedule Schedule) *LimitSchedule {
+	return &LimitSchedule{N: n, Schedule: schedule}
+}
+
+// Next 返回被包装调度的下一次激活时间，如果激活次数已经用完则返回零时间。
+func (schedule *LimitSchedule) Next(t time.Time) time.Time {
+	schedule.mu.Lock()
+	defer schedule.mu.Unlock()
+	if !schedule.last.IsZero() && !t.Before(schedule.last) {
+		schedule.used++
+		schedule.last = time.Time{}
+	}
+	if schedule.used >= schedule.N {
+		return time.Time{}
+	}
+	next := schedule.Schedule.Next(t)
+	schedule.last = next
+	return next
+}
+
+// next 返回条目在 t 之后的下一次激活时间，遵守条目的生命周期限制。
+// 如果条目已经达到 MaxRuns 或超过 EndAt，则返回零时间。
+func (e *Entry) next(t time.Time) time.Time {
+	if e.MaxRuns > 0 && e.RunCount >= e.MaxRuns {
+		return time.Time{}
+	}
+	return nextBetween(e.StartAt, e.EndAt, e.Schedule, t)
+}
+
+// ended 报告条目是否结束，即永远不会再激活：它没有下一次激活时间，
+// 并且它的生命周期是有限的（设置了 MaxRuns 或 EndAt），或者它的调度是有限的
+// （例如一次性调度或 Limit）。暂停的条目和计划不可满足的其他条目不算结束，它们像以前一样保留。
+func (e *Entry) ended() bool {
+	if e.Paused || !e.Next.IsZero() {
+		return false
+	}
+	if e.MaxRuns > 0 || !e.EndAt.IsZero() {
+		return true
+	}
+	return isBounded(e.Schedule)
+}
+
+// boundedSchedule 由激活次数或时间有限的调度实现。
+// 当 bounded 返回 true 时，Next 返回零时间表示调度永远不会再激活，Cron 随后删除它的条目。
+type boundedSchedule interface {
+	bounded() bool
+}
+
+// isBounded 报告 schedule 是否是有限的调度。
+func isBounded(schedule Schedule) bool {
+	s, ok := schedule.(boundedSchedule)
+	return ok && s.bounded()
+}
+
+func (schedule BetweenSchedule) bounded() bool {
+	return !schedule.End.IsZero() || isBounded(schedule.Schedule)
+}
+
+func (schedule BetweenSchedule) onceTime() (time.Time, bool) { return onceTimeOf(schedule.Schedule) }
+
+func (schedule *LimitSchedule) bounded() bool { return true }
+
+func (schedule *LimitSchedule) onceTime() (time.Time, bool) { return onceTimeOf(schedule.Schedule) }
@@ -0,0 +1,146 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBetweenScheduleNext(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	hourly, _ := ParseStandard("CRON_TZ=UTC 0 * * * *")
+
+	tests := []struct {
+		schedule Schedule
+		time     time.Time
+		expected time.Time
+	}{
+		// 开始之前的激活被跳过，恰好在开始时的激活被包含。
+		{Between(start, end, hourly), start.Add(-5 * time.Hour), start},
+		{Between(start, end, hourly), start, start.Add(time.Hour)},
+		// 结束时的激活被包含，之后的被丢弃。
+		{Between(start, end, hourly), end.Add(-time.Minute), end},
+		{Between(start, end, hourly), end, time.Time{}},
+		// 零值表示没有限制。
+		{Between(time.Time{}, end, hourly), start.Add(-5 * time.Hour), start.Add(-4 * time.Hour)},
+		{Between(start, time.Time{}, hourly), end, end.Add(time.Hour)},
+	}
+	for _, c := range tests {
+		if actual := c.schedule.Next(c.time); !actual.Equal(c.expected) {
+			t.Errorf("%v => expected %v, got %v", c.time, c.expected, actual)
+		}
+	}
+}
+
+func TestLimitScheduleNext(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule := Limit(2, Every(time.Minute))
+
+	first := schedule.Next(start)
+	// 在激活到来之前重新计算不会消耗次数。
+	if again := schedule.Next(start.Add(time.Second)); !again.Equal(start.Add(time.Minute + time.Second)) {
+		t.Errorf("unexpected recomputed activation %v", again)
+	}
+	second := schedule.Next(first.Add(time.Second))
+	if second.IsZero() {
+		t.Fatal("expected a second activation")
+	}
+	if third := schedule.Next(second); !third.IsZero() {
+		t.Errorf("expected no third activation, got %v", third)
+	}
+}
+
+// 测试达到生命周期的条目被删除，并且运行次数被记录。
+func TestEntryLifetime(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	limited, _ := cron.AddFunc("@hourly", func() {}, WithMaxRuns(2))
+	bounded, _ := cron.AddFunc("@hourly", func() {},
+		WithStartAt(start.Add(90*time.Minute)), WithEndAt(start.Add(150*time.Minute)))
+	cron.Start()
+	defer cron.Stop()
+
+	if next := cron.Entry(bounded).Next; !next.Equal(start.Add(90 * time.Minute)) {
+		t.Errorf("expected bounded entry to start at %v, got %v", start.Add(90*time.Minute), next)
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	clock.BlockUntil(1)
+	if entry := cron.Entry(limited); entry.RunCount != 1 || entry.MaxRuns != 2 {
+		t.Errorf("expected 1 of 2 runs, got %d of %d", entry.RunCount, entry.MaxRuns)
+	}
+
+	for i := 0; i < 3; i++ {
+		clock.Advance(time.Hour)
+		clock.BlockUntil(1)
+	}
+	if cron.Entry(limited).Valid() {
+		t.Error("expected entry to be removed after its last run")
+	}
+	if cron.Entry(bounded).Valid() {
+		t.Error("expected entry to be removed after its end")
+	}
+}
+
+// 测试启动时已经超过 EndAt 的条目被删除，而不是以零 Next 保留。
+func TestEntryEndedBeforeStart(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	ended, _ := cron.AddFunc("@hourly", func() {}, WithEndAt(start.Add(-time.Hour)))
+	paused, _ := cron.AddFunc("@hourly", func() {}, WithEndAt(start.Add(time.Hour)))
+	cron.Pause(paused)
+	cron.Start()
+	defer cron.Stop()
+
+	if cron.Entry(ended).Valid() {
+		t.Error("expected entry past its end to be removed on start")
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(2 * time.Hour)
+	clock.BlockUntil(1)
+	if err := cron.Resume(paused); err != nil {
+		t.Fatal(err)
+	}
+	if cron.Entry(paused).Valid() {
+		t.Error("expected entry resumed past its end to be removed")
+	}
+	if n := len(cron.Entries()); n != 0 {
+		t.Errorf("expected no entries, got %d", n)
+	}
+}
+
+// lastSchedule 激活一次之后不再激活，但不是有限的调度。
+type lastSchedule struct{ at time.Time }
+
+func (s lastSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.at) {
+		return s.at
+	}
+	return time.Time{}
+}
+
+// 测试没有下一次激活时间的自定义调度像以前一样保留在 Cron 中，
+// 而有限的调度和有生命周期限制的条目被删除。
+func TestEntryWithoutNextKept(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	custom := cron.Schedule(lastSchedule{start.Add(time.Hour)}, FuncJob(func() {}))
+	limited := cron.Schedule(Limit(1, Every(time.Hour)), FuncJob(func() {}))
+	cron.Start()
+	defer cron.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	clock.BlockUntil(1)
+	if entry := cron.Entry(custom); !entry.Valid() || !entry.Next.IsZero() {
+		t.Errorf("expected the custom entry to be kept with a zero next, got %+v", entry)
+	}
+	if cron.Entry(limited).Valid() {
+		t.Error("expected the limited entry to be removed after its last run")
+	}
+}
@@ -0,0 +1,101 @@
+package cron
+
+import (
+	"context"
+	"time"
+)
+
+// Listener 接收调度器生命周期和作业执行的类型化事件，
+// 可用于驱动指标、审计日志和告警，而无需解析日志行。
+//
+// 调度器和条目事件在调度循环中同步调用，作业事件在运行作业的 goroutine 中调用，
+// 因此实现必须是并发安全的，并且应该快速返回。
+//
+// 在调度循环中调用的方法（OnSchedulerStart、OnMisfire，以及调度器运行时的
+// OnEntryAdded 和 OnEntryRemoved）不能同步调用 Cron 的方法，例如 Entries 或 Entry，
+// 否则调度循环会等待它自己而死锁；需要时请在新的 goroutine 中调用。
+// OnSchedulerStop 和作业事件可以调用 Cron 的方法。
+// 嵌入 NopListener 可以只实现感兴趣的方法。
+type Listener interface {
+	// OnSchedulerStart 在调度器启动并计算了所有条目的首次激活时间后调用。
+	OnSchedulerStart()
+	// OnSchedulerStop 在调度器停止时调用。正在运行的作业可能仍未完成。
+	OnSchedulerStop()
+	// OnEntryAdded 在条目被添加到 Cron 时调用。
+	OnEntryAdded(entry Entry)
+	// OnEntryRemoved 在条目被删除时调用，包括生命周期结束后被自动删除的条目。
+	OnEntryRemoved(entry Entry)
+	// OnJobStart 在作业开始运行之前调用。
+	OnJobStart(event JobEvent)
+	// OnJobEnd 在作业运行结束后调用，包括作业返回错误或发生 panic 的情况。
+	OnJobEnd(event JobEvent)
+	// OnMisfire 在条目错过了计划在 scheduled 的激活时调用，
+	// 之后按照条目的 MisfirePolicy 处理。
+	OnMisfire(entry Entry, scheduled time.Time)
+}
+
+// JobEvent 描述一次作业运行。
+type JobEvent struct {
+	// Entry 是作业开始运行时条目的快照。
+	Entry Entry
+	// Scheduled 是这次运行对应的计划激活时间；通过 RunNow 手动触发的运行为零时间。
+	Scheduled time.Time
+	// Start 是作业开始运行的时间。
+	Start time.Time
+	// Duration 是作业运行的时长，仅在 OnJobEnd 中设置。
+	Duration time.Duration
+	// Err 是作业返回的错误，仅在 OnJobEnd 中设置。由 Recover 恢复的 panic 是 *PanicError。
+	Err error
+	// Panic 是作业中未被恢复的 panic 的值，仅在 OnJobEnd 中设置。
+	// 监听器被通知后，panic 会继续传播。
+	Panic interface{}
+}
+
+// NopListener 是不做任何事情的 Listener。
+type NopListener struct{}
+
+func (NopListener) OnSchedulerStart()          {}
+func (NopListener) OnSchedulerStop()           {}
+func (NopListener) OnEntryAdded(Entry)         {}
+func (NopListener) OnEntryRemoved(Entry)       {}
+func (NopListener) OnJobStart(JobEvent)        {}
+func (NopListener) OnJobEnd(JobEvent)          {}
+func (NopListener) OnMisfire(Entry, time.Time) {}
+
+// notifyEntryAdded 向监听器报告新添加的条目。
+func (c *Cron) notifyEntryAdded(entry Entry) {
+	for _, l := range c.listeners {
+		l.OnEntryAdded(entry)
+	}
+}
+
+// notifyEntryRemoved 向监听器报告被删除的条目。
+func (c *Cron) notifyEntryRemoved(entry Entry) {
+	for _, l := range c.listeners {
+		l.OnEntryRemoved(entry)
+	}
+}
+
+// runJob 运行作业，并向监听器报告它的开始和结束。
+func (c *Cron) runJob(ctx context.Context, j Job, event JobEvent) (err error) {
+	if len(c.listeners) == 0 {
+		return RunWithContext(ctx, j)
+	}
+
+	event.Start = c.now()
+	for _, l := range c.listeners {
+		l.OnJobStart(event)
+	}
+	defer func() {
+		event.Duration = c.now().Sub(event.Start)
+		event.Err = err
+		event.Panic = recover()
+		for _, l := range c.listeners {
+			l.OnJobEnd(event)
+		}
+		if event.Panic != nil {
+			panic(event.Panic)
+		}
+	}()
+	return RunWithContext(ctx, j)
+}
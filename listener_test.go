@@ -0,0 +1,166 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingListener 记录它收到的事件，格式为"事件名:条目ID"。
+type recordingListener struct {
+	NopListener
+	mu     sync.Mutex
+	events []string
+	ends   []JobEvent
+}
+
+func (l *recordingListener) record(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *recordingListener) Events() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.events...)
+}
+
+func (l *recordingListener) Ends() []JobEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]JobEvent(nil), l.ends...)
+}
+
+func (l *recordingListener) OnSchedulerStart()              { l.record("start") }
+func (l *recordingListener) OnSchedulerStop()               { l.record("stop") }
+func (l *recordingListener) OnEntryAdded(e Entry)           { l.record(fmt.Sprint("added:", e.ID)) }
+func (l *recordingListener) OnEntryRemoved(e Entry)         { l.record(fmt.Sprint("removed:", e.ID)) }
+func (l *recordingListener) OnJobStart(ev JobEvent)         { l.record(fmt.Sprint("job-start:", ev.Entry.ID)) }
+func (l *recordingListener) OnMisfire(e Entry, _ time.Time) { l.record(fmt.Sprint("misfire:", e.ID)) }
+
+func (l *recordingListener) OnJobEnd(ev JobEvent) {
+	l.record(fmt.Sprint("job-end:", ev.Entry.ID))
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ends = append(l.ends, ev)
+}
+
+func TestListener(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	var listener recordingListener
+	cron, clock := newWithFakeClock(start, WithListener(&listener),
+		WithChain(Recover(DiscardLogger)), WithLogger(DiscardLogger))
+
+	failure := errors.New("failure")
+	failing, _ := cron.AddFuncErr("@hourly", func(context.Context) error { return failure })
+	once, _ := cron.AddOnce(start.Add(30*time.Minute), func() { panic("once") })
+	cron.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	clock.BlockUntil(1)
+	clock.Advance(2 * time.Hour)
+	clock.BlockUntil(1)
+	<-cron.Stop().Done()
+
+	events := listener.Events()
+	expected := []string{
+		"added:1", "added:2", "start",
+		"job-start:1", "job-end:1", "job-start:2", "job-end:2", "removed:2",
+		"misfire:1", "job-start:1", "job-end:1",
+		"stop",
+	}
+	// 作业事件来自不同的 goroutine，因此只比较事件的集合以及顺序确定的前缀。
+	if !reflect.DeepEqual(sorted(events), sorted(expected)) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+	if !reflect.DeepEqual(events[:3], expected[:3]) {
+		t.Errorf("unexpected lifecycle order: %v", events)
+	}
+
+	for _, ev := range listener.Ends() {
+		switch ev.Entry.ID {
+		case failing:
+			if ev.Err != failure {
+				t.Errorf("expected job error %v, got %v", failure, ev.Err)
+			}
+			if ev.Scheduled.IsZero() || ev.Start.Before(ev.Scheduled) {
+				t.Errorf("unexpected scheduled/start times: %v / %v", ev.Scheduled, ev.Start)
+			}
+		case once:
+			var panicErr *PanicError
+			if !errors.As(ev.Err, &panicErr) || panicErr.Value != "once" {
+				t.Errorf("expected recovered panic, got %v", ev.Err)
+			}
+		}
+	}
+}
+
+func TestListenerUnrecoveredPanic(t *testing.T) {
+	var listener recordingListener
+	cron := New(WithListener(&listener), WithChain())
+	event := JobEvent{Entry: Entry{ID: 1}}
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("expected panic to propagate, got %v", r)
+			}
+		}()
+		cron.runJob(context.Background(), FuncJob(func() { panic("boom") }), event)
+	}()
+
+	ends := listener.Ends()
+	if len(ends) != 1 || ends[0].Panic != "boom" {
+		t.Errorf("expected job end with panic, got %+v", ends)
+	}
+}
+
+func sorted(s []string) []string {
+	s = append([]string(nil), s...)
+	sort.Strings(s)
+	return s
+}
+
+// reentrantListener 在不在调度循环中调用的事件中调用 Cron 的方法。
+type reentrantListener struct {
+	NopListener
+	cron    *Cron
+	entries []int
+}
+
+func (l *reentrantListener) OnEntryAdded(Entry) { l.entries = append(l.entries, len(l.cron.Entries())) }
+func (l *reentrantListener) OnEntryRemoved(Entry) {
+	l.entries = append(l.entries, len(l.cron.Entries()))
+}
+func (l *reentrantListener) OnSchedulerStop() { l.entries = append(l.entries, len(l.cron.Entries())) }
+
+// 测试 OnSchedulerStop 以及调度器停止时的条目事件可以调用 Cron 的方法而不会死锁。
+func TestListenerReentrant(t *testing.T) {
+	listener := &reentrantListener{}
+	cron := New(WithListener(listener))
+	listener.cron = cron
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		id, _ := cron.AddFunc("@every 1h", func() {})
+		cron.Start()
+		<-cron.Stop().Done()
+		cron.Remove(id)
+	}()
+	select {
+	case <-done:
+	case <-time.After(OneSecond):
+		t.Fatal("expected listener calling back into Cron not to deadlock")
+	}
+	if expected := []int{1, 1, 0}; !reflect.DeepEqual(listener.entries, expected) {
+		t.Errorf("expected %v, got %v", expected, listener.entries)
+	}
+}
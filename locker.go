@@ -0,0 +1,116 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-utils2/time2"
+)
+
+// Locker 是多个副本之间共享的锁服务，用于保证每次激活只在一个副本上运行。
+//
+// 锁是带有租期的：TryLock 成功后，锁在 ttl 之后自动过期，即使持有者没有调用 Unlock。
+// 这样崩溃的副本不会永远持有锁。
+type Locker interface {
+	// TryLock 尝试获取给定键的锁，不会等待其他持有者释放它。
+	// 如果获取了锁，返回 true；如果锁被其他持有者持有且尚未过期，返回 false。
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Unlock 在锁过期之前释放给定键的锁。
+	Unlock(ctx context.Context, key string) error
+}
+
+// DistributedLock 在运行作业之前使用 locker 获取本次激活的锁，
+// 使在多个副本上运行相同条目的 Cron 中只有一个副本运行每次激活。
+// 锁的键由条目名称（未命名的条目使用 ID）和 RunInfo.Activation 组成，
+// 因此各副本必须为条目使用相同的名称。使用 JitterSchedule 时，各副本的随机延迟不同，
+// 但同一次激活的锁键相同。
+//
+// 运行结束后锁不会被释放，而是一直保持到 ttl 过期，
+// 这样时钟稍慢的副本不会在锁释放后再次运行同一次激活。ttl 应该大于副本之间的时钟偏差。
+//
+// 如果锁被其他副本持有，运行被跳过，它在Info级别记录跳过并返回 ErrSkipped。
+// 如果 locker 返回错误，作业不会运行，错误被返回。
+// 手动触发的运行和不是由 Cron 启动的运行没有计划激活时间，它们不获取锁而直接运行。
+//
+// 如果 ttl 不是正数，它会恐慌：这样的锁立即过期，每个副本都会获得它。
+func DistributedLock(locker Locker, ttl time.Duration, logger Logger) JobWrapper {
+	checkLockTTL(ttl)
+	return func(j Job) Job {
+		return FuncErrJob(func(ctx context.Context) error {
+			info, ok := RunInfoFromContext(ctx)
+			if !ok || info.Scheduled.IsZero() {
+				return RunWithContext(ctx, j)
+			}
+			key := lockKey(info)
+			locked, err := locker.TryLock(ctx, key, ttl)
+			if err != nil {
+				return fmt.Errorf("failed to lock %s: %w", key, err)
+			}
+			if !locked {
+				logger.Info("skip", "entry", info.ID, "lock", key)
+				return ErrSkipped
+			}
+			return RunWithContext(ctx, j)
+		})
+	}
+}
+
+// checkLockTTL 在锁的租期不是正数时恐慌。
+func checkLockTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		panic(fmt.Sprintf("cron: lock ttl must be positive, got %v", ttl))
+	}
+}
+
+// lockKey 返回一次激活的锁键。
+func lockKey(info RunInfo) string {
+	name := info.Name
+	if name == "" {
+		name = fmt.Sprint("entry-", info.ID)
+	}
+	activation := info.Activation
+	if activation.IsZero() {
+		activation = info.Scheduled
+	}
+	return fmt.Sprintf("%s@%d", name, activation.Unix())
+}
+
+// MemoryLocker 是在进程内存中保存锁的 Locker，
+// 适用于同一进程中的多个 Cron 实例以及测试。
+type MemoryLocker struct {
+	mu    sync.Mutex
+	locks map[string]time.Time
+	now   func() time.Time
+}
+
+// NewMemoryLocker 返回一个没有任何锁的 MemoryLocker。
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{locks: make(map[string]time.Time), now: time2.Now}
+}
+
+// TryLock 实现 Locker。
+func (l *MemoryLocker) TryLock(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	for k, expiry := range l.locks {
+		if !expiry.After(now) {
+			delete(l.locks, k)
+		}
+	}
+	if _, ok := l.locks[key]; ok {
+		return false, nil
+	}
+	l.locks[key] = now.Add(ttl)
+	return true, nil
+}
+
+// Unlock 实现 Locker。
+func (l *MemoryLocker) Unlock(_ context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.locks, key)
+	return nil
+}
@@ -0,0 +1,180 @@
+package cron
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	locker := NewMemoryLocker()
+	locker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if ok, _ := locker.TryLock(ctx, "key", time.Minute); !ok {
+		t.Fatal("expected to acquire a free lock")
+	}
+	if ok, _ := locker.TryLock(ctx, "key", time.Minute); ok {
+		t.Error("expected a held lock to be refused")
+	}
+	if ok, _ := locker.TryLock(ctx, "other", time.Minute); !ok {
+		t.Error("expected locks on other keys to be independent")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := locker.TryLock(ctx, "key", time.Minute); !ok {
+		t.Error("expected an expired lock to be acquired again")
+	}
+	locker.Unlock(ctx, "key")
+	if ok, _ := locker.TryLock(ctx, "key", time.Minute); !ok {
+		t.Error("expected an unlocked lock to be acquired again")
+	}
+}
+
+func TestDistributedLock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	locker := NewMemoryLocker()
+	var runs int64
+
+	// 两个共享同一个 Locker 的副本运行相同的命名条目。
+	var clocks []*FakeClock
+	var replicas []*Cron
+	for i := 0; i < 2; i++ {
+		cron, clock := newWithFakeClock(start,
+			WithLocker(locker, time.Hour), WithLogger(DiscardLogger))
+		cron.AddNamedFunc("report", "@hourly", func() { atomic.AddInt64(&runs, 1) })
+		cron.Start()
+		clocks = append(clocks, clock)
+		replicas = append(replicas, cron)
+	}
+
+	for hour := 0; hour < 3; hour++ {
+		for _, clock := range clocks {
+			clock.BlockUntil(1)
+			clock.Advance(time.Hour)
+		}
+	}
+	for _, cron := range replicas {
+		<-cron.Stop().Done()
+	}
+
+	if runs != 3 {
+		t.Errorf("expected each activation to run once across replicas, got %d runs", runs)
+	}
+}
+
+// 测试各副本为同一次激活抽取不同的随机延迟时，锁仍然只让一个副本运行它。
+func TestDistributedLockWithJitter(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	locker := NewMemoryLocker()
+	var runs int64
+
+	var clocks []*FakeClock
+	var replicas []*Cron
+	for i := 0; i < 2; i++ {
+		cron, clock := newWithFakeClock(start,
+			WithLocker(locker, time.Hour), WithLogger(DiscardLogger))
+		hourly, _ := ParseStandard("@hourly")
+		schedule := JitterWithSource(5*time.Minute, hourly, rand.NewSource(int64(i)))
+		cron.Schedule(schedule, FuncJob(func() { atomic.AddInt64(&runs, 1) }), WithName("report"))
+		cron.Start()
+		clocks = append(clocks, clock)
+		replicas = append(replicas, cron)
+	}
+
+	for hour := 0; hour < 3; hour++ {
+		for _, clock := range clocks {
+			clock.BlockUntil(1)
+			clock.Advance(time.Hour)
+		}
+	}
+	for _, cron := range replicas {
+		<-cron.Stop().Done()
+	}
+
+	if runs != 3 {
+		t.Errorf("expected each jittered activation to run once across replicas, got %d runs", runs)
+	}
+}
+
+// 测试没有获得锁的副本不等待全局工作池的工作槽。
+func TestDistributedLockBeforeWorkerPool(t *testing.T) {
+	locker := NewMemoryLocker()
+	cron := New(WithChain(), WithLogger(DiscardLogger), WithLocker(locker, time.Hour),
+		WithMaxConcurrentJobs(1, QueuePolicy{Mode: QueueBlock}))
+	id, _ := cron.AddNamedFunc("report", "@hourly", func() {})
+
+	at := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)
+	info := RunInfo{ID: id, Name: "report", Scheduled: at, Activation: at}
+	ctx := context.WithValue(context.Background(), runInfoKey{}, info)
+	// 另一个副本已经获得了这次激活的锁，并且唯一的工作槽被占用。
+	locker.TryLock(ctx, lockKey(info), time.Hour)
+	cron.pool.slots <- struct{}{}
+
+	done := make(chan error, 1)
+	go func() { done <- RunWithContext(ctx, cron.Entry(id).WrappedJob) }()
+	select {
+	case err := <-done:
+		if err != ErrSkipped {
+			t.Errorf("expected ErrSkipped, got %v", err)
+		}
+	case <-time.After(OneSecond):
+		t.Fatal("expected a replica that lost the lock not to wait for a worker slot")
+	}
+}
+
+func TestDistributedLockRunsManualTriggers(t *testing.T) {
+	locker := NewMemoryLocker()
+	locker.TryLock(context.Background(), "report@0", time.Hour)
+	job := DistributedLock(locker, time.Hour, DiscardLogger)(FuncErrJob(func(context.Context) error {
+		return nil
+	}))
+	ctx := context.WithValue(context.Background(), runInfoKey{}, RunInfo{ID: 1, Name: "report"})
+	if err := RunWithContext(ctx, job); err != nil {
+		t.Errorf("expected a manual run to bypass the lock, got %v", err)
+	}
+}
+
+func TestDistributedLockInvalidTTL(t *testing.T) {
+	for _, f := range []func(){
+		func() { New(WithLocker(NewMemoryLocker(), 0)) },
+		func() { DistributedLock(NewMemoryLocker(), -time.Second, DiscardLogger) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a non-positive ttl to panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestRunInfoFromContext(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	infos := make(chan RunInfo, 1)
+	id, _ := cron.AddNamedJob("report", "@hourly", ContextJob{FuncJobCtx(func(ctx context.Context) {
+		info, _ := RunInfoFromContext(ctx)
+		infos <- info
+	})})
+	cron.Start()
+	defer cron.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	at := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)
+	expected := RunInfo{ID: id, Name: "report", Scheduled: at, Activation: at}
+	if info := <-infos; info != expected {
+		t.Errorf("expected %+v, got %+v", expected, info)
+	}
+
+	if _, ok := RunInfoFromContext(context.Background()); ok {
+		t.Error("expected no run info outside a run")
+	}
+}
@@ -0,0 +1,127 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package cron
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-utils2/time2"
+)
+
+// FileLocker 是使用本地目录中的租约文件实现的 Locker。
+// 每个键对应目录中的一个文件，其中记录锁的过期时间；
+// 对租约文件的检查和修改由目录中 .lock 文件上的 flock 保护，
+// 因此共享同一目录的多个进程可以安全地竞争同一个键。
+//
+// 它适用于运行在同一台机器上，或共享支持 flock 的文件系统的副本。
+type FileLocker struct {
+	dir string
+	now func() time.Time
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewFileLocker 返回一个在 dir 中保存租约文件的 FileLocker，必要时创建该目录。
+func NewFileLocker(dir string) (*FileLocker, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileLocker{dir: dir, now: time2.Now}, nil
+}
+
+// TryLock 实现 Locker。
+func (l *FileLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	unlock, err := l.lockDir()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	now := l.now()
+	l.prune(now, ttl)
+	path := l.path(key)
+	if expiry, ok := readLease(path); ok && expiry.After(now) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, []byte(now.Add(ttl).Format(time.RFC3339Nano)), 0o644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Unlock 实现 Locker。
+func (l *FileLocker) Unlock(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	unlock, err := l.lockDir()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := os.Remove(l.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path 返回给定键的租约文件的路径。
+func (l *FileLocker) path(key string) string {
+	return filepath.Join(l.dir, url.PathEscape(key)+".lease")
+}
+
+// lockDir 获取保护租约文件的 flock，返回释放它的函数。
+func (l *FileLocker) lockDir() (func(), error) {
+	f, err := os.OpenFile(filepath.Join(l.dir, ".lock"), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", l.dir, err)
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// prune 删除已经过期的租约文件。为了避免每次加锁都扫描目录，
+// 它最多每个 ttl 执行一次。调用者必须持有目录的 flock。
+func (l *FileLocker) prune(now time.Time, ttl time.Duration) {
+	l.mu.Lock()
+	if now.Sub(l.lastPrune) < ttl {
+		l.mu.Unlock()
+		return
+	}
+	l.lastPrune = now
+	l.mu.Unlock()
+
+	paths, _ := filepath.Glob(filepath.Join(l.dir, "*.lease"))
+	for _, path := range paths {
+		if expiry, ok := readLease(path); !ok || !expiry.After(now) {
+			os.Remove(path)
+		}
+	}
+}
+
+// readLease 读取租约文件中的过期时间。如果文件不存在或无法解析，ok 为 false。
+func readLease(path string) (expiry time.Time, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	expiry, err = time.Parse(time.RFC3339Nano, string(data))
+	return expiry, err == nil
+}
@@ -0,0 +1,83 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package cron
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileLocker(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 两个实例分别打开目录中的锁文件，与两个进程的行为相同。
+	var lockers []*FileLocker
+	for i := 0; i < 2; i++ {
+		locker, err := NewFileLocker(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		locker.now = func() time.Time { return now }
+		lockers = append(lockers, locker)
+	}
+	ctx := context.Background()
+
+	if ok, err := lockers[0].TryLock(ctx, "report@1", time.Minute); !ok || err != nil {
+		t.Fatalf("expected to acquire a free lock, got %v, %v", ok, err)
+	}
+	if ok, _ := lockers[1].TryLock(ctx, "report@1", time.Minute); ok {
+		t.Error("expected a lock held by another locker to be refused")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := lockers[1].TryLock(ctx, "report@1", time.Minute); !ok {
+		t.Error("expected an expired lock to be acquired")
+	}
+	if err := lockers[1].Unlock(ctx, "report@1"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := lockers[0].TryLock(ctx, "report@1", time.Minute); !ok {
+		t.Error("expected an unlocked lock to be acquired")
+	}
+}
+
+func TestFileLockerPrunesExpiredLeases(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	locker, _ := NewFileLocker(dir)
+	locker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	locker.TryLock(ctx, "report@1", time.Minute)
+	now = now.Add(2 * time.Minute)
+	locker.TryLock(ctx, "report@2", time.Minute)
+
+	if _, err := os.Stat(filepath.Join(dir, "report@1.lease")); !os.IsNotExist(err) {
+		t.Errorf("expected the expired lease to be removed, got %v", err)
+	}
+}
+
+func TestFileLockerConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	var acquired int64
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locker, _ := NewFileLocker(dir)
+			if ok, _ := locker.TryLock(context.Background(), "report@1", time.Minute); ok {
+				atomic.AddInt64(&acquired, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if acquired != 1 {
+		t.Errorf("expected exactly one locker to acquire the lock, got %d", acquired)
+	}
+}
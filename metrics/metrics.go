@@ -0,0 +1,262 @@
+// Package metrics 为 cron 调度器提供 Prometheus 风格的指标。
+//
+// Collector 作为 cron.Listener 挂接到 Cron 上，统计作业的运行、失败、panic、
+// 跳过和错过的激活，记录运行时长以及计划激活时间与实际开始时间之间的调度延迟，
+// 并通过 http.Handler 以 Prometheus 文本格式导出它们：
+//
+//	collector := metrics.NewCollector()
+//	c := cron.New(cron.WithListener(collector))
+//	http.Handle("/metrics", collector)
+//
+// 指标按条目标记：有名称的条目使用它的名称，否则使用它的 ID。
+// 由于条目 ID 只在一个 Cron 实例中唯一，每个 Cron 应使用自己的 Collector。
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cron "github.com/go-utils2/cron2"
+)
+
+// DefaultBuckets 是运行时长和调度延迟直方图默认使用的桶上界（秒）。
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// Collector 收集 Cron 的指标并以 Prometheus 文本格式导出。
+// 它实现了 cron.Listener 和 http.Handler。Collector 不应在多个 Cron 实例之间共享，
+// 否则不同实例中 ID 相同的未命名条目的指标会被合并。
+type Collector struct {
+	mu       sync.Mutex
+	buckets  []float64
+	entries  int
+	runs     map[string]float64
+	failures map[string]float64
+	panics   map[string]float64
+	skips    map[string]float64
+	misfires map[string]float64
+	duration map[string]*histogram
+	lag      map[string]*histogram
+}
+
+var _ cron.Listener = (*Collector)(nil)
+
+// NewCollector 返回一个使用 DefaultBuckets 的 Collector。
+func NewCollector() *Collector {
+	return NewCollectorWithBuckets(DefaultBuckets)
+}
+
+// NewCollectorWithBuckets 返回一个使用给定直方图桶上界（秒）的 Collector。
+func NewCollectorWithBuckets(buckets []float64) *Collector {
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Collector{
+		buckets:  buckets,
+		runs:     make(map[string]float64),
+		failures: make(map[string]float64),
+		panics:   make(map[string]float64),
+		skips:    make(map[string]float64),
+		misfires: make(map[string]float64),
+		duration: make(map[string]*histogram),
+		lag:      make(map[string]*histogram),
+	}
+}
+
+// OnSchedulerStart 实现 cron.Listener。
+func (c *Collector) OnSchedulerStart() {}
+
+// OnSchedulerStop 实现 cron.Listener。
+func (c *Collector) OnSchedulerStop() {}
+
+// OnEntryAdded 实现 cron.Listener。
+func (c *Collector) OnEntryAdded(cron.Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries++
+}
+
+// OnEntryRemoved 实现 cron.Listener。
+func (c *Collector) OnEntryRemoved(cron.Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries--
+}
+
+// OnJobStart 记录计划激活时间与实际开始时间之间的调度延迟。
+// 手动触发的运行没有计划激活时间，因此不记录延迟。
+func (c *Collector) OnJobStart(event cron.JobEvent) {
+	if event.Scheduled.IsZero() {
+		return
+	}
+	lag := event.Start.Sub(event.Scheduled)
+	if lag < 0 {
+		lag = 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.histogram(c.lag, label(event.Entry)).observe(lag)
+}
+
+// OnJobEnd 统计作业运行的结果及其时长。被跳过的运行（以 ErrSkipped 结束的运行，
+// 包括超出并发上限、被工作池丢弃和没有获得分布式锁的运行）只计入跳过次数。
+func (c *Collector) OnJobEnd(event cron.JobEvent) {
+	name := label(event.Entry)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if errors.Is(event.Err, cron.ErrSkipped) {
+		c.skips[name]++
+		return
+	}
+	c.runs[name]++
+	c.histogram(c.duration, name).observe(event.Duration)
+
+	var panicErr *cron.PanicError
+	switch {
+	case event.Panic != nil || errors.As(event.Err, &panicErr):
+		c.panics[name]++
+		c.failures[name]++
+	case event.Err != nil:
+		c.failures[name]++
+	}
+}
+
+// OnMisfire 实现 cron.Listener。
+func (c *Collector) OnMisfire(entry cron.Entry, _ time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.misfires[label(entry)]++
+}
+
+// ServeHTTP 以 Prometheus 文本格式输出所有指标。
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo 将所有指标以 Prometheus 文本格式写入 w。
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var sb strings.Builder
+	writeHeader(&sb, "cron_entries", "gauge", "Number of entries scheduled.")
+	fmt.Fprintf(&sb, "cron_entries %d\n", c.entries)
+
+	counters := []struct {
+		name, help string
+		values     map[string]float64
+	}{
+		{"cron_job_runs_total", "Total number of job runs, excluding skipped runs.", c.runs},
+		{"cron_job_failures_total", "Total number of job runs that returned an error or panicked.", c.failures},
+		{"cron_job_panics_total", "Total number of job runs that panicked.", c.panics},
+		{"cron_job_skips_total", "Total number of job runs skipped by a concurrency limit, dropped by the worker pool or lost to another replica's lock.", c.skips},
+		{"cron_job_misfires_total", "Total number of activations missed because the scheduler was behind.", c.misfires},
+	}
+	for _, counter := range counters {
+		writeHeader(&sb, counter.name, "counter", counter.help)
+		for _, name := range sortedKeys(counter.values) {
+			fmt.Fprintf(&sb, "%s{entry=%s} %s\n", counter.name, quote(name), formatFloat(counter.values[name]))
+		}
+	}
+
+	histograms := []struct {
+		name, help string
+		values     map[string]*histogram
+	}{
+		{"cron_job_duration_seconds", "Duration of job runs in seconds.", c.duration},
+		{"cron_schedule_lag_seconds", "Delay between the scheduled activation and the actual start of a job in seconds.", c.lag},
+	}
+	for _, h := range histograms {
+		writeHeader(&sb, h.name, "histogram", h.help)
+		names := make([]string, 0, len(h.values))
+		for name := range h.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			h.values[name].write(&sb, h.name, quote(name), c.buckets)
+		}
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// histogram 返回给定条目的直方图，必要时创建它。调用者必须持有 c.mu。
+func (c *Collector) histogram(m map[string]*histogram, name string) *histogram {
+	h, ok := m[name]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(c.buckets)), buckets: c.buckets}
+		m[name] = h
+	}
+	return h
+}
+
+// histogram 是一个累积桶计数的简单直方图。
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	v := d.Seconds()
+	for i, upper := range h.buckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+func (h *histogram) write(sb *strings.Builder, name, entry string, buckets []float64) {
+	for i, upper := range buckets {
+		fmt.Fprintf(sb, "%s_bucket{entry=%s,le=%q} %d\n", name, entry, formatFloat(upper), h.counts[i])
+	}
+	fmt.Fprintf(sb, "%s_bucket{entry=%s,le=\"+Inf\"} %d\n", name, entry, h.count)
+	fmt.Fprintf(sb, "%s_sum{entry=%s} %s\n", name, entry, formatFloat(h.sum))
+	fmt.Fprintf(sb, "%s_count{entry=%s} %d\n", name, entry, h.count)
+}
+
+// label 返回条目在指标中的标签值：有名称时使用名称，否则使用 ID。
+func label(entry cron.Entry) string {
+	if entry.Name != "" {
+		return entry.Name
+	}
+	return strconv.Itoa(int(entry.ID))
+}
+
+func writeHeader(sb *strings.Builder, name, typ, help string) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// quote 按照 Prometheus 文本格式转义并引用标签值。
+func quote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
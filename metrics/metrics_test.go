@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	cron "github.com/go-utils2/cron2"
+)
+
+func TestCollector(t *testing.T) {
+	collector := NewCollectorWithBuckets([]float64{1, 10})
+	backup := cron.Entry{ID: 1, Name: "backup"}
+	report := cron.Entry{ID: 2}
+	scheduled := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	collector.OnEntryAdded(backup)
+	collector.OnEntryAdded(report)
+	collector.OnEntryRemoved(report)
+	collector.OnJobStart(cron.JobEvent{Entry: backup, Scheduled: scheduled, Start: scheduled.Add(2 * time.Second)})
+	collector.OnJobEnd(cron.JobEvent{Entry: backup, Duration: 500 * time.Millisecond})
+	collector.OnJobEnd(cron.JobEvent{Entry: backup, Duration: 5 * time.Second, Err: errors.New("failed")})
+	collector.OnJobEnd(cron.JobEvent{Entry: backup, Err: &cron.PanicError{Value: "boom"}})
+	collector.OnJobEnd(cron.JobEvent{Entry: backup, Err: cron.ErrSkipped})
+	collector.OnJobEnd(cron.JobEvent{Entry: report, Duration: time.Minute})
+	collector.OnMisfire(report, scheduled)
+
+	var sb strings.Builder
+	collector.WriteTo(&sb)
+	out := sb.String()
+
+	for _, expected := range []string{
+		"# TYPE cron_entries gauge\ncron_entries 1\n",
+		`cron_job_runs_total{entry="backup"} 3`,
+		`cron_job_runs_total{entry="2"} 1`,
+		`cron_job_failures_total{entry="backup"} 2`,
+		`cron_job_panics_total{entry="backup"} 1`,
+		`cron_job_skips_total{entry="backup"} 1`,
+		`cron_job_misfires_total{entry="2"} 1`,
+		"# TYPE cron_job_duration_seconds histogram",
+		`cron_job_duration_seconds_bucket{entry="backup",le="1"} 2`,
+		`cron_job_duration_seconds_bucket{entry="backup",le="10"} 3`,
+		`cron_job_duration_seconds_bucket{entry="backup",le="+Inf"} 3`,
+		`cron_job_duration_seconds_sum{entry="backup"} 5.5`,
+		`cron_job_duration_seconds_count{entry="2"} 1`,
+		`cron_schedule_lag_seconds_bucket{entry="backup",le="1"} 0`,
+		`cron_schedule_lag_seconds_bucket{entry="backup",le="10"} 1`,
+		`cron_schedule_lag_seconds_sum{entry="backup"} 2`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+}
+
+// 测试条目数量是计数而不是按标签去重的集合。
+func TestCollectorEntries(t *testing.T) {
+	collector := NewCollector()
+	collector.OnEntryAdded(cron.Entry{ID: 1})
+	collector.OnEntryAdded(cron.Entry{ID: 1})
+	collector.OnEntryRemoved(cron.Entry{ID: 1})
+
+	var sb strings.Builder
+	collector.WriteTo(&sb)
+	if !strings.Contains(sb.String(), "cron_entries 1\n") {
+		t.Errorf("expected 1 entry, got:\n%s", sb.String())
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if actual := quote("a\"b\\c\nd"); actual != `"a\"b\\c\nd"` {
+		t.Errorf("unexpected quoted label %s", actual)
+	}
+}
+
+// 测试挂接到 Cron 的 Collector 通过 HTTP 导出指标。
+func TestCollectorWithCron(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	clock := cron.NewFakeClock(start)
+	collector := NewCollector()
+	c := cron.New(
+		cron.WithClock(clock),
+		cron.WithLocation(time.UTC),
+		cron.WithListener(collector),
+		cron.WithChain(cron.Recover(cron.DiscardLogger)),
+		cron.WithLogger(cron.DiscardLogger),
+	)
+	c.AddNamedFunc("hourly", "@hourly", func() {})
+	c.AddFuncErr("@hourly", func(context.Context) error { return errors.New("failed") })
+	c.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	clock.BlockUntil(1)
+	<-c.Stop().Done()
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %s", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	for _, expected := range []string{
+		"cron_entries 2",
+		`cron_job_runs_total{entry="hourly"} 1`,
+		`cron_job_runs_total{entry="2"} 1`,
+		`cron_job_failures_total{entry="2"} 1`,
+		`cron_schedule_lag_seconds_count{entry="hourly"} 1`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, body)
+		}
+	}
+}
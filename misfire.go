@@ -0,0 +1,86 @@
+package cron
+
+import "time"
+
+// defaultMisfireGrace 是 MisfirePolicy.Grace 为零时使用的宽限期。
+// 定时器总是会稍晚于激活时间触发，因此需要一个非零的容差。
+const defaultMisfireGrace = time.Second
+
+// MisfireMode 决定条目错过激活时间时的处理方式。
+type MisfireMode int
+
+const (
+	// MisfireRunOnce 将所有错过的激活合并为一次立即运行。这是默认行为。
+	MisfireRunOnce MisfireMode = iota
+	// MisfireSkip 跳过错过的激活，等待下一次按计划激活。
+	MisfireSkip
+	// MisfireRunAll 为每个错过的激活运行一次作业，最多 MaxRuns 次。
+	MisfireRunAll
+)
+
+// MisfirePolicy 描述当调度器落后（例如进程休眠、GC 暂停或长时间停止）
+// 导致条目错过激活时间时的处理方式。它借鉴了 Quartz 的 misfire 指令。
+//
+// 只有延迟超过 Grace 的激活才被视为错过；在宽限期内的激活总是正常运行。
+type MisfirePolicy struct {
+	// Mode 是错过激活时采取的动作。
+	Mode MisfireMode
+
+	// Grace 是激活被视为错过之前允许的最大延迟。零值表示一秒。
+	Grace time.Duration
+
+	// MaxRuns 限制 MisfireRunAll 模式下补跑的次数。零值表示不限制。
+	MaxRuns int
+}
+
+func (p MisfirePolicy) grace() time.Duration {
+	if p.Grace <= 0 {
+		return defaultMisfireGrace
+	}
+	return p.Grace
+}
+
+// fire 按照条目的 misfire 策略运行到期的条目，并计算它的下一次激活时间。
+func (c *Cron) fire(e *Entry, now time.Time) {
+	defer c.saveEntry(e)
+
+	policy := e.MisfirePolicy
+	if now.Sub(e.Next) <= policy.grace() {
+		c.activate(e, e.Next)
+		e.Next = e.next(now)
+		return
+	}
+
+	c.logger.Info("misfire", "now", now, "entry", e.ID, "scheduled", e.Next)
+	for _, l := range c.listeners {
+		l.OnMisfire(e.snapshot(), e.Next)
+	}
+	switch policy.Mode {
+	case MisfireSkip:
+	case MisfireRunAll:
+		runs := 0
+		for t := e.Next; !t.IsZero() && !t.After(now); t = e.next(t) {
+			if policy.MaxRuns > 0 && runs >= policy.MaxRuns {
+				break
+			}
+			c.activate(e, t)
+			runs++
+		}
+	default:
+		c.activate(e, e.Next)
+	}
+	e.Next = e.next(now)
+}
+
+// activate 为计划在 t 的激活运行条目的作业，并记录这次运行。
+// 跟随者不运行作业，但同样记录这次激活，使 Prev 和 RunCount 在所有实例上保持一致，
+// MaxRuns 在领导权转移之后仍然有效。
+func (c *Cron) activate(e *Entry, t time.Time) {
+	if c.isLeader() {
+		c.startJob(e, t)
+	} else {
+		c.logger.Info("follower", "entry", e.ID, "scheduled", t)
+	}
+	e.Prev = t
+	e.RunCount++
+}
@@ -0,0 +1,75 @@
+package cron
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// runMissed 在假时钟上启动一个每小时的条目，让调度器一次性落后三个小时，
+// 并返回作业运行的次数以及条目的快照。
+func runMissed(t *testing.T, opts []Option, entryOpts ...EntryOption) (int64, Entry) {
+	t.Helper()
+	start := time.Date(2026, 3, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start, opts...)
+
+	var calls int64
+	id, _ := cron.AddFunc("@hourly", func() { atomic.AddInt64(&calls, 1) }, entryOpts...)
+	cron.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(3 * time.Hour)
+	clock.BlockUntil(1)
+	<-cron.Stop().Done()
+	return atomic.LoadInt64(&calls), cron.Entry(id)
+}
+
+func TestMisfirePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   MisfirePolicy
+		runs     int64
+		prevHour int
+	}{
+		{"run once by default", MisfirePolicy{}, 1, 1},
+		{"skip", MisfirePolicy{Mode: MisfireSkip}, 0, 0},
+		{"run all", MisfirePolicy{Mode: MisfireRunAll}, 3, 3},
+		{"run all up to max", MisfirePolicy{Mode: MisfireRunAll, MaxRuns: 2}, 2, 2},
+		{"within grace", MisfirePolicy{Mode: MisfireSkip, Grace: 3 * time.Hour}, 1, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runs, entry := runMissed(t, []Option{WithMisfirePolicy(test.policy)})
+			if runs != test.runs {
+				t.Errorf("expected %d runs, got %d", test.runs, runs)
+			}
+			if expected := time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC); !entry.Next.Equal(expected) {
+				t.Errorf("expected next %v, got %v", expected, entry.Next)
+			}
+			if test.prevHour == 0 {
+				if !entry.Prev.IsZero() {
+					t.Errorf("expected zero prev, got %v", entry.Prev)
+				}
+			} else if entry.Prev.Hour() != test.prevHour {
+				t.Errorf("expected prev at hour %d, got %v", test.prevHour, entry.Prev)
+			}
+			if entry.MisfirePolicy != test.policy {
+				t.Errorf("expected entry policy %+v, got %+v", test.policy, entry.MisfirePolicy)
+			}
+		})
+	}
+}
+
+func TestEntryMisfirePolicyOverridesDefault(t *testing.T) {
+	policy := MisfirePolicy{Mode: MisfireRunAll}
+	runs, entry := runMissed(t,
+		[]Option{WithMisfirePolicy(MisfirePolicy{Mode: MisfireSkip})},
+		WithEntryMisfirePolicy(policy))
+	if runs != 3 {
+		t.Errorf("expected 3 runs, got %d", runs)
+	}
+	if entry.MisfirePolicy != policy {
+		t.Errorf("expected entry policy %+v, got %+v", policy, entry.MisfirePolicy)
+	}
+}
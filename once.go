@@ -0,0 +1,61 @@
+package cron
+
+import "time"
+
+// OnceSchedule 表示在绝对时间只激活一次的调度，例如"明天14:00"。
+// 激活之后 Next 返回零时间，Cron 随后会删除该条目。
+type OnceSchedule struct {
+	Time time.Time
+}
+
+// At 返回一个在给定时间激活一次的调度。
+// 如果 Cron 计算条目的首次激活时间时该时间已经过去，条目立即到期，
+// 并按照它的 misfire 策略运行或跳过，之后被删除。
+func At(t time.Time) OnceSchedule {
+	return OnceSchedule{Time: t}
+}
+
+// Next 如果给定时间早于激活时间则返回激活时间，否则返回零时间。
+func (schedule OnceSchedule) Next(t time.Time) time.Time {
+	if t.Before(schedule.Time) {
+		return schedule.Time
+	}
+	return time.Time{}
+}
+
+func (schedule OnceSchedule) bounded() bool { return true }
+
+func (schedule OnceSchedule) onceTime() (time.Time, bool) { return schedule.Time, true }
+
+// onceSchedule 由一次性调度以及包装了其他调度的装饰器（例如 JitterSchedule 和 Limit）实现，
+// 使被装饰的一次性调度同样可以被识别。
+type onceSchedule interface {
+	// onceTime 返回一次性调度的激活时间。如果调度不是一次性的，ok 为 false。
+	onceTime() (t time.Time, ok bool)
+}
+
+// onceTimeOf 返回 schedule 作为一次性调度的激活时间。如果它不是一次性的，ok 为 false。
+func onceTimeOf(schedule Schedule) (time.Time, bool) {
+	if s, ok := schedule.(onceSchedule); ok {
+		return s.onceTime()
+	}
+	return time.Time{}, false
+}
+
+// overdueNext 如果条目是时间已经过去且从未运行的一次性条目，则返回它的激活时间，
+// 使它立即到期，由它的 misfire 策略决定是否运行，之后被删除。否则返回零时间。
+func (e *Entry) overdueNext() time.Time {
+	if at, ok := onceTimeOf(e.Schedule); ok && e.Prev.IsZero() {
+		return e.next(at.Add(-time.Nanosecond))
+	}
+	return time.Time{}
+}
+
+// resumeNext 返回条目恢复或重新调度时从 now 开始的下一次激活时间。
+// 与 initEntry 一样，时间已经过去的一次性条目立即到期。
+func (e *Entry) resumeNext(now time.Time) time.Time {
+	if next := e.next(now); !next.IsZero() {
+		return next
+	}
+	return e.overdueNext()
+}
@@ -0,0 +1,150 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOnceScheduleNext(t *testing.T) {
+	at := time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC)
+	schedule := At(at)
+	tests := []struct {
+		time     time.Time
+		expected time.Time
+	}{
+		{at.Add(-time.Hour), at},
+		{at.Add(-time.Nanosecond), at},
+		{at, time.Time{}},
+		{at.Add(time.Hour), time.Time{}},
+	}
+	for _, c := range tests {
+		if actual := schedule.Next(c.time); !actual.Equal(c.expected) {
+			t.Errorf("%v => expected %v, got %v", c.time, c.expected, actual)
+		}
+	}
+}
+
+// 测试一次性条目在运行后被删除。
+func TestAddOnce(t *testing.T) {
+	start := time.Date(2026, 10, 31, 14, 0, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	ran := make(chan time.Time, 1)
+	id, err := cron.AddOnce(start.Add(24*time.Hour), func() { ran <- clock.Now() })
+	if err != nil {
+		t.Fatal(err)
+	}
+	cron.AddFunc("@every 1h", func() {})
+	cron.Start()
+	defer cron.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(24 * time.Hour)
+	select {
+	case <-ran:
+	case <-time.After(OneSecond):
+		t.Fatal("expected one-shot job runs")
+	}
+
+	clock.BlockUntil(1)
+	if cron.Entry(id).Valid() {
+		t.Error("expected one-shot entry to be removed after it ran")
+	}
+	if n := len(cron.Entries()); n != 1 {
+		t.Errorf("expected 1 remaining entry, got %d", n)
+	}
+}
+
+// 测试启动之前已经过去的一次性条目按照 misfire 策略处理，并在之后被删除。
+func TestAddOncePast(t *testing.T) {
+	tests := []struct {
+		policy MisfirePolicy
+		runs   int
+	}{
+		{MisfirePolicy{}, 1},
+		{MisfirePolicy{Mode: MisfireSkip}, 0},
+	}
+	for _, c := range tests {
+		start := time.Date(2026, 10, 31, 14, 0, 0, 0, time.UTC)
+		cron, clock := newWithFakeClock(start, WithMisfirePolicy(c.policy))
+
+		ran := make(chan struct{}, 1)
+		id, _ := cron.AddOnce(start.Add(time.Minute), func() { ran <- struct{}{} })
+		clock.Advance(2 * time.Minute)
+		cron.Start()
+
+		clock.BlockUntil(1)
+		<-cron.Stop().Done()
+		if runs := len(ran); runs != c.runs {
+			t.Errorf("%+v: expected %d runs, got %d", c.policy, c.runs, runs)
+		}
+		if cron.Entry(id).Valid() {
+			t.Errorf("%+v: expected past one-shot entry to be removed", c.policy)
+		}
+	}
+}
+
+// 测试暂停期间时间已经过去的一次性条目在恢复时立即到期，运行后被删除。
+func TestAddOnceResumedPast(t *testing.T) {
+	start := time.Date(2026, 10, 31, 14, 0, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start)
+
+	ran := make(chan struct{}, 1)
+	id, _ := cron.AddOnce(start.Add(time.Minute), func() { ran <- struct{}{} })
+	cron.Pause(id)
+	cron.Start()
+	defer cron.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(2 * time.Minute)
+	if err := cron.Resume(id); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ran:
+	case <-time.After(OneSecond):
+		t.Fatal("expected resumed one-shot job runs")
+	}
+
+	clock.BlockUntil(1)
+	if cron.Entry(id).Valid() {
+		t.Error("expected resumed one-shot entry to be removed after it ran")
+	}
+}
+
+// 测试被装饰的一次性调度（例如带随机延迟的）同样在时间过去后立即到期，并在运行后被删除。
+func TestDecoratedOncePast(t *testing.T) {
+	start := time.Date(2026, 10, 31, 14, 0, 0, 0, time.UTC)
+	past := start.Add(time.Minute)
+	tests := []func(cron *Cron, cmd func()) EntryID{
+		func(cron *Cron, cmd func()) EntryID {
+			return cron.Schedule(Jitter(time.Minute, At(past)), FuncJob(cmd))
+		},
+		func(cron *Cron, cmd func()) EntryID {
+			return cron.Schedule(Limit(1, At(past)), FuncJob(cmd))
+		},
+		func(cron *Cron, cmd func()) EntryID {
+			id, err := cron.AddFunc("@at "+past.Format(time.RFC3339)+" ~1m", cmd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return id
+		},
+	}
+	for i, add := range tests {
+		cron, clock := newWithFakeClock(start, WithParser(jitterParser))
+		ran := make(chan struct{}, 1)
+		id := add(cron, func() { ran <- struct{}{} })
+		clock.Advance(time.Hour)
+		cron.Start()
+
+		clock.BlockUntil(1)
+		<-cron.Stop().Done()
+		if len(ran) != 1 {
+			t.Errorf("case %d: expected the past one-shot job to run once", i)
+		}
+		if cron.Entry(id).Valid() {
+			t.Errorf("case %d: expected the past one-shot entry to be removed", i)
+		}
+	}
+}
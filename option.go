@@ -7,6 +7,9 @@ import (
 // Option 表示对Cron默认行为的修改。
 type Option func(*Cron)
 
+// EntryOption 表示对单个条目的修改，在添加作业时传入。
+type EntryOption func(*Entry)
+
 // WithLocation 覆盖cron实例的时区。
 func WithLocation(loc *time.Location) Option {
 	return func(c *Cron) {
@@ -43,3 +46,144 @@ func WithLogger(logger Logger) Option {
 		c.logger = logger
 	}
 }
+
+// WithClock 覆盖调度循环使用的时钟。
+// 这主要用于测试：安装 FakeClock 可以在不真实等待的情况下
+// 确定性地触发到期的条目。
+func WithClock(clock Clock) Option {
+	return func(c *Cron) {
+		c.clock = clock
+	}
+}
+
+// WithJobTimeout 为每次运行的上下文设置截止时间。
+// 超过 d 后，可感知上下文的作业收到的上下文将被取消；
+// 不检查上下文的作业不受影响。零值表示没有截止时间。
+func WithJobTimeout(d time.Duration) Option {
+	return func(c *Cron) {
+		c.jobTimeout = d
+	}
+}
+
+// WithListener 注册一个监听器，接收调度器生命周期和作业执行的事件。
+// 可以多次使用以注册多个监听器，它们按注册顺序被调用。
+func WithListener(l Listener) Option {
+	return func(c *Cron) {
+		c.listeners = append(c.listeners, l)
+	}
+}
+
+// WithLocker 使用 locker 协调运行相同条目的多个副本，使每次激活只在一个副本上运行。
+// 它等价于用 DistributedLock(locker, ttl, logger) 包装每个条目的作业，
+// 因此各副本应使用 WithName 为条目设置相同的名称。如果 ttl 不是正数，它会恐慌。
+func WithLocker(locker Locker, ttl time.Duration) Option {
+	checkLockTTL(ttl)
+	return func(c *Cron) {
+		c.locker = locker
+		c.lockTTL = ttl
+	}
+}
+
+// WithLeaderElector 使多个 Cron 实例中只有被 elector 选为领导者的实例运行作业。
+// 选举在调度器启动时开始，在停止时结束。
+func WithLeaderElector(elector LeaderElector) Option {
+	return func(c *Cron) {
+		c.elector = elector
+	}
+}
+
+// WithMaxConcurrentJobs 将所有条目同时运行的作业总数限制为 n。
+// 当所有工作槽都被占用时，新的运行按照给定的 QueuePolicy 等待或被丢弃，
+// 被丢弃的运行以 ErrSkipped 报告给监听器。如果 n 不是正数，它会恐慌。
+func WithMaxConcurrentJobs(n int, policy QueuePolicy) Option {
+	return func(c *Cron) {
+		c.pool = newWorkerPool(n, policy)
+	}
+}
+
+// WithMisfirePolicy 设置条目错过激活时间时的默认处理策略。
+// 单个条目可以使用 WithEntryMisfirePolicy 覆盖它。
+func WithMisfirePolicy(policy MisfirePolicy) Option {
+	return func(c *Cron) {
+		c.misfirePolicy = policy
+	}
+}
+
+// WithEntryMisfirePolicy 覆盖单个条目错过激活时间时的处理策略。
+func WithEntryMisfirePolicy(policy MisfirePolicy) EntryOption {
+	return func(e *Entry) {
+		e.MisfirePolicy = policy
+	}
+}
+
+// WithJobStore 使用给定的 JobStore 持久化命名条目的运行状态，
+// 使 Prev 和 Next 能够在进程重启后保留。
+func WithJobStore(store JobStore) Option {
+	return func(c *Cron) {
+		c.store = store
+	}
+}
+
+// WithName 为条目设置一个稳定的名称。名称在 Cron 实例中必须是唯一的，
+// 可以用于 EntryByName 和 RemoveByName，并用作 JobStore 的键。
+func WithName(name string) EntryOption {
+	return func(e *Entry) {
+		e.Name = name
+	}
+}
+
+// WithStartAt 使条目的激活不早于给定时间。
+func WithStartAt(t time.Time) EntryOption {
+	return func(e *Entry) {
+		e.StartAt = t
+	}
+}
+
+// WithEndAt 使条目的激活不晚于给定时间。最后一次激活之后条目被删除。
+func WithEndAt(t time.Time) EntryOption {
+	return func(e *Entry) {
+		e.EndAt = t
+	}
+}
+
+// WithMaxRuns 限制条目按计划运行的次数。达到该次数后条目被删除。
+func WithMaxRuns(n int) EntryOption {
+	return func(e *Entry) {
+		e.MaxRuns = n
+	}
+}
+
+// WithEntryMaxConcurrent 限制单个条目同时运行的次数。
+// 已经有 n 次运行在进行中时，新的运行被跳过，就像 SkipIfStillRunning 一样。
+// 如果 n 不是正数，它会恐慌。
+func WithEntryMaxConcurrent(n int) EntryOption {
+	if n <= 0 {
+		panic("cron: concurrency limit must be positive")
+	}
+	return func(e *Entry) {
+		e.MaxConcurrent = n
+	}
+}
+
+// WithTags 为条目添加标签，可用于 EntriesWithTag。
+func WithTags(tags ...string) EntryOption {
+	return func(e *Entry) {
+		l := e.copyLabels()
+		l.tags = append(l.tags, tags...)
+		e.labels = l
+	}
+}
+
+// WithMetadata 为条目添加元数据键值对。
+func WithMetadata(metadata map[string]string) EntryOption {
+	return func(e *Entry) {
+		l := e.copyLabels()
+		if l.metadata == nil {
+			l.metadata = make(map[string]string, len(metadata))
+		}
+		for k, v := range metadata {
+			l.metadata[k] = v
+		}
+		e.labels = l
+	}
+}
@@ -40,3 +40,11 @@ func TestWithVerboseLogger(t *testing.T) {
 		t.Error("expected to see some actions, got:", out)
 	}
 }
+
+func TestWithClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New(WithClock(clock))
+	if c.clock != clock {
+		t.Error("expected provided clock")
+	}
+}
@@ -24,6 +24,12 @@ const (
 	Dow                                    // 周中日字段，默认 *
 	DowOptional                            // 可选周中日字段，默认 *
 	Descriptor                             // 允许描述符，如 @monthly、@weekly 等。
+	LastDay                                // 允许月中日的 L、L-n 和周中日的 nL。
+	WeekdayRules                           // 允许月中日的 nW、LW 和周中日的 n#m。
+	Year                                   // 年字段，位于周中日之后，默认 *
+	YearOptional                           // 可选年字段，默认 *
+	Hash                                   // 允许由哈希种子确定的 H、H(a-b) 和 H/n。
+	JitterSuffix                           // 允许在规范末尾使用 ~<duration> 添加随机延迟。
 )
 
 var places = []ParseOption{
@@ -75,6 +81,9 @@ func NewParser(options ParseOption) Parser {
 	if options&SecondOptional > 0 {
 		optionals++
 	}
+	if options&YearOptional > 0 {
+		optionals++
+	}
 	if optionals > 1 {
 		panic("multiple optionals may not be configured")
 	}
@@ -85,10 +94,32 @@ func NewParser(options ParseOption) Parser {
 // 如果规范无效，它返回描述性错误。
 // 它接受由 NewParser 配置的 crontab 规范和功能。
 func (p Parser) Parse(spec string) (Schedule, error) {
+	return p.ParseWithSeed(spec, "")
+}
+
+// ParseWithSeed 类似于 Parse，但使用 seed 确定启用 Hash 选项时 "H" 字段的值。
+// 相同的规范和种子总是得到相同的计划。没有种子时，包含 "H" 的规范会返回错误。
+func (p Parser) ParseWithSeed(spec, seed string) (Schedule, error) {
 	if len(spec) == 0 {
 		return nil, fmt.Errorf("empty spec string")
 	}
 
+	// 如果已配置并且存在则提取随机延迟，例如 "@every 1h ~5m"
+	if i := strings.LastIndex(spec, " ~"); i >= 0 && p.options&JitterSuffix > 0 {
+		maxDelay, err := time.ParseDuration(strings.TrimSpace(spec[i+2:]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jitter %s: %s", spec[i+1:], err)
+		}
+		if maxDelay < 0 {
+			return nil, fmt.Errorf("jitter should not be negative: %s", spec[i+1:])
+		}
+		schedule, err := p.ParseWithSeed(strings.TrimSpace(spec[:i]), seed)
+		if err != nil {
+			return nil, err
+		}
+		return Jitter(maxDelay, schedule), nil
+	}
+
 	// 如果存在则提取时区
 	var loc = time.Local
 	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
@@ -112,12 +143,18 @@ func (p Parser) Parse(spec string) (Schedule, error) {
 	// 按空白字符分割。
 	fields := strings.Fields(spec)
 
-	// 验证并填充任何省略或可选字段
+	// 分离年字段，然后验证并填充任何省略或可选字段
+	fields, yearField := splitYear(fields, p.options)
 	var err error
 	fields, err = normalizeFields(fields, p.options)
 	if err != nil {
 		return nil, err
 	}
+	if p.options&Hash > 0 {
+		if fields, err = expandHash(fields, seed); err != nil {
+			return nil, err
+		}
+	}
 
 	field := func(field string, r bounds) uint64 {
 		if err != nil {
@@ -128,17 +165,33 @@ func (p Parser) Parse(spec string) (Schedule, error) {
 		return bits
 	}
 
+	dayField := func(field string, r bounds, parseRule func(string) (DayRule, bool, error)) (uint64, []DayRule) {
+		if err != nil {
+			return 0, nil
+		}
+		var (
+			bits  uint64
+			rules []DayRule
+		)
+		bits, rules, err = getDayField(field, r, parseRule)
+		return bits, rules
+	}
+
 	var (
-		second     = field(fields[0], seconds)
-		minute     = field(fields[1], minutes)
-		hour       = field(fields[2], hours)
-		dayofmonth = field(fields[3], dom)
-		month      = field(fields[4], months)
-		dayofweek  = field(fields[5], dow)
+		second               = field(fields[0], seconds)
+		minute               = field(fields[1], minutes)
+		hour                 = field(fields[2], hours)
+		dayofmonth, domRules = dayField(fields[3], dom, p.parseDomRule)
+		month                = field(fields[4], months)
+		dayofweek, dowRules  = dayField(fields[5], dow, p.parseDowRule)
 	)
 	if err != nil {
 		return nil, err
 	}
+	years, err := getYears(yearField)
+	if err != nil {
+		return nil, err
+	}
 
 	return &SpecSchedule{
 		Second:   second,
@@ -147,6 +200,9 @@ func (p Parser) Parse(spec string) (Schedule, error) {
 		Dom:      dayofmonth,
 		Month:    month,
 		Dow:      dayofweek,
+		DomRules: domRules,
+		DowRules: dowRules,
+		Years:    years,
 		Location: loc,
 	}, nil
 }
@@ -213,6 +269,101 @@ func normalizeFields(fields []string, options ParseOption) ([]string, error) {
 	return expandedFields, nil
 }
 
+// splitYear 在解析器包含年字段并且提供了它时，从字段末尾分离出年字段。
+// 未提供的可选年字段使用默认值 "*"。
+func splitYear(fields []string, options ParseOption) ([]string, string) {
+	switch {
+	case options&Year > 0:
+		if len(fields) > 0 {
+			return fields[:len(fields)-1], fields[len(fields)-1]
+		}
+	case options&YearOptional > 0:
+		count := 0
+		for _, place := range places {
+			if options&place > 0 {
+				count++
+			}
+		}
+		if len(fields) == count+1 {
+			return fields[:count], fields[count]
+		}
+	}
+	return fields, "*"
+}
+
+// getYears 返回年字段表示的年份，按升序排列。年字段与其他字段使用相同的语法，
+// 但由于年份无法放入位集，它返回年份列表；如果字段匹配任何年份，则返回 nil。
+func getYears(field string) ([]int, error) {
+	var matched [maxYear - minYear + 1]bool
+	ranges := strings.FieldsFunc(field, func(r rune) bool { return r == ',' })
+	for _, expr := range ranges {
+		var (
+			start, end, step uint
+			rangeAndStep     = strings.Split(expr, "/")
+			lowAndHigh       = strings.Split(rangeAndStep[0], "-")
+			err              error
+		)
+		if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
+			start, end = minYear, maxYear
+		} else {
+			if start, err = mustParseInt(lowAndHigh[0]); err != nil {
+				return nil, err
+			}
+			switch len(lowAndHigh) {
+			case 1:
+				end = start
+			case 2:
+				if end, err = mustParseInt(lowAndHigh[1]); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, fmt.Errorf("too many hyphens: %s", expr)
+			}
+		}
+
+		switch len(rangeAndStep) {
+		case 1:
+			step = 1
+		case 2:
+			if step, err = mustParseInt(rangeAndStep[1]); err != nil {
+				return nil, err
+			}
+			if len(lowAndHigh) == 1 {
+				end = maxYear
+			}
+		default:
+			return nil, fmt.Errorf("too many slashes: %s", expr)
+		}
+
+		if start < minYear {
+			return nil, fmt.Errorf("beginning of range (%d) below minimum (%d): %s", start, minYear, expr)
+		}
+		if end > maxYear {
+			return nil, fmt.Errorf("end of range (%d) above maximum (%d): %s", end, maxYear, expr)
+		}
+		if start > end {
+			return nil, fmt.Errorf("beginning of range (%d) beyond end of range (%d): %s", start, end, expr)
+		}
+		if step == 0 {
+			return nil, fmt.Errorf("step of range should be a positive number: %s", expr)
+		}
+		if start == minYear && end == maxYear && step == 1 {
+			return nil, nil
+		}
+		for y := start; y <= end; y += step {
+			matched[y-minYear] = true
+		}
+	}
+
+	var years []int
+	for i, ok := range matched {
+		if ok {
+			years = append(years, minYear+i)
+		}
+	}
+	return years, nil
+}
+
 var standardParser = NewParser(
 	Minute | Hour | Dom | Month | Dow | Descriptor,
 )
@@ -430,5 +581,14 @@ func parseDescriptor(descriptor string, loc *time.Location) (Schedule, error) {
 		return Every(duration), nil
 	}
 
+	const at = "@at "
+	if strings.HasPrefix(descriptor, at) {
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(descriptor[len(at):]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time %s: %s", descriptor, err)
+		}
+		return At(t), nil
+	}
+
 	return nil, fmt.Errorf("unrecognized descriptor: %s", descriptor)
 }
@@ -122,6 +122,7 @@ func TestParseScheduleErrors(t *testing.T) {
 	var tests = []struct{ expr, err string }{
 		{"* 5 j * * *", "failed to parse int from"},
 		{"@every Xm", "failed to parse duration"},
+		{"@at tomorrow", "failed to parse time"},
 		{"@unrecognized", "unrecognized descriptor"},
 		{"* * * *", "expected 5 to 6 fields"},
 		{"", "empty spec string"},
@@ -150,6 +151,7 @@ func TestParseSchedule(t *testing.T) {
 		{standardParser, "CRON_TZ=UTC  5 * * * *", every5min(time.UTC)},
 		{secondParser, "CRON_TZ=Asia/Tokyo 0 5 * * * *", every5min(tokyo)},
 		{secondParser, "@every 5m", ConstantDelaySchedule{5 * time.Minute}},
+		{secondParser, "@at 2026-11-01T14:00:00Z", At(time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC))},
 		{secondParser, "@midnight", midnight(time.Local)},
 		{secondParser, "TZ=UTC  @midnight", midnight(time.UTC)},
 		{secondParser, "TZ=Asia/Tokyo @midnight", midnight(tokyo)},
@@ -319,8 +321,16 @@ func TestStandardSpecSchedule(t *testing.T) {
 		err      string
 	}{
 		{
-			expr:     "5 * * * *",
-			expected: &SpecSchedule{1 << seconds.min, 1 << 5, all(hours), all(dom), all(months), all(dow), time.Local},
+			expr: "5 * * * *",
+			expected: &SpecSchedule{
+				Second:   1 << seconds.min,
+				Minute:   1 << 5,
+				Hour:     all(hours),
+				Dom:      all(dom),
+				Month:    all(months),
+				Dow:      all(dow),
+				Location: time.Local,
+			},
 		},
 		{
 			expr:     "@every 5m",
@@ -359,15 +369,15 @@ func TestNoDescriptorParser(t *testing.T) {
 }
 
 func every5min(loc *time.Location) *SpecSchedule {
-	return &SpecSchedule{1 << 0, 1 << 5, all(hours), all(dom), all(months), all(dow), loc}
+	return &SpecSchedule{Second: 1 << 0, Minute: 1 << 5, Hour: all(hours), Dom: all(dom), Month: all(months), Dow: all(dow), Location: loc}
 }
 
 func every5min5s(loc *time.Location) *SpecSchedule {
-	return &SpecSchedule{1 << 5, 1 << 5, all(hours), all(dom), all(months), all(dow), loc}
+	return &SpecSchedule{Second: 1 << 5, Minute: 1 << 5, Hour: all(hours), Dom: all(dom), Month: all(months), Dow: all(dow), Location: loc}
 }
 
 func midnight(loc *time.Location) *SpecSchedule {
-	return &SpecSchedule{1, 1, 1, all(dom), all(months), all(dow), loc}
+	return &SpecSchedule{Second: 1, Minute: 1, Hour: 1, Dom: all(dom), Month: all(months), Dow: all(dow), Location: loc}
 }
 
 func annual(loc *time.Location) *SpecSchedule {
@@ -381,3 +391,156 @@ func annual(loc *time.Location) *SpecSchedule {
 		Location: loc,
 	}
 }
+
+func TestParseLastDay(t *testing.T) {
+	parser := NewParser(Minute | Hour | Dom | Month | Dow | LastDay)
+	entries := []struct {
+		expr               string
+		dom, dow           uint64
+		domRules, dowRules []DayRule
+	}{
+		{"0 0 L * *", 0, all(dow), []DayRule{{Kind: LastDayOfMonth}}, nil},
+		{"0 0 1,L-2 * *", 1 << 1, all(dow), []DayRule{{Kind: LastDayOfMonth, N: 2}}, nil},
+		{"0 0 ? * 5L", all(dom), 0, nil, []DayRule{{Kind: LastWeekdayOfMonth, Weekday: time.Friday}}},
+		{"0 0 ? * mon,satL", all(dom), 1 << 1, nil, []DayRule{{Kind: LastWeekdayOfMonth, Weekday: time.Saturday}}},
+	}
+
+	for _, c := range entries {
+		actual, err := parser.Parse(c.expr)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", c.expr, err)
+			continue
+		}
+		spec := actual.(*SpecSchedule)
+		if spec.Dom != c.dom || spec.Dow != c.dow {
+			t.Errorf("%s => expected dom %b and dow %b, got %b and %b", c.expr, c.dom, c.dow, spec.Dom, spec.Dow)
+		}
+		if !reflect.DeepEqual(spec.DomRules, c.domRules) || !reflect.DeepEqual(spec.DowRules, c.dowRules) {
+			t.Errorf("%s => expected rules %v %v, got %v %v", c.expr, c.domRules, c.dowRules, spec.DomRules, spec.DowRules)
+		}
+	}
+}
+
+func TestParseLastDayErrors(t *testing.T) {
+	parser := NewParser(Minute | Hour | Dom | Month | Dow | LastDay)
+	var tests = []struct {
+		parser    Parser
+		expr, err string
+	}{
+		{standardParser, "0 0 L * *", "failed to parse int from"},
+		{standardParser, "0 0 * * 5L", "failed to parse int from"},
+		{parser, "0 0 L-31 * *", "above maximum"},
+		{parser, "0 0 L-x * *", "failed to parse int from"},
+		{parser, "0 0 * * 7L", "above maximum"},
+		{parser, "0 0 * * xL", "failed to parse int from"},
+		{parser, "0 L * * *", "failed to parse int from"},
+	}
+	for _, c := range tests {
+		actual, err := c.parser.Parse(c.expr)
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s => expected %v, got %v", c.expr, c.err, err)
+		}
+		if actual != nil {
+			t.Errorf("expected nil schedule on error, got %v", actual)
+		}
+	}
+}
+
+func TestParseWeekdayRules(t *testing.T) {
+	parser := NewParser(Minute | Hour | Dom | Month | Dow | WeekdayRules)
+	entries := []struct {
+		expr               string
+		domRules, dowRules []DayRule
+	}{
+		{"0 0 15W * *", []DayRule{{Kind: NearestWeekday, N: 15}}, nil},
+		{"0 0 lw * *", []DayRule{{Kind: NearestWeekday}}, nil},
+		{"0 0 ? * MON#1", nil, []DayRule{{Kind: NthWeekdayOfMonth, N: 1, Weekday: time.Monday}}},
+		{"0 0 ? * 2#3", nil, []DayRule{{Kind: NthWeekdayOfMonth, N: 3, Weekday: time.Tuesday}}},
+	}
+
+	for _, c := range entries {
+		actual, err := parser.Parse(c.expr)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", c.expr, err)
+			continue
+		}
+		spec := actual.(*SpecSchedule)
+		if !reflect.DeepEqual(spec.DomRules, c.domRules) || !reflect.DeepEqual(spec.DowRules, c.dowRules) {
+			t.Errorf("%s => expected rules %v %v, got %v %v", c.expr, c.domRules, c.dowRules, spec.DomRules, spec.DowRules)
+		}
+	}
+
+	var invalid = []struct {
+		parser    Parser
+		expr, err string
+	}{
+		{standardParser, "0 0 15W * *", "failed to parse int from"},
+		{standardParser, "0 0 * * MON#1", "failed to parse int from"},
+		{parser, "0 0 32W * *", "out of range"},
+		{parser, "0 0 xW * *", "failed to parse int from"},
+		{parser, "0 0 * * MON#6", "out of range"},
+		{parser, "0 0 * * MON#1#2", "too many hashes"},
+		{parser, "0 0 * * 7#1", "above maximum"},
+	}
+	for _, c := range invalid {
+		if _, err := c.parser.Parse(c.expr); err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s => expected %v, got %v", c.expr, c.err, err)
+		}
+	}
+}
+
+func TestParseYears(t *testing.T) {
+	entries := []struct {
+		parser Parser
+		expr   string
+		years  []int
+	}{
+		{NewParser(Minute | Hour | Dom | Month | Dow | Year), "0 0 * * * *", nil},
+		{NewParser(Minute | Hour | Dom | Month | Dow | Year), "0 0 * * * 2026", []int{2026}},
+		{NewParser(Minute | Hour | Dom | Month | Dow | Year), "0 0 * * * 2026-2028,2030", []int{2026, 2027, 2028, 2030}},
+		{NewParser(Minute | Hour | Dom | Month | Dow | Year), "0 0 * * * 2026-2032/3", []int{2026, 2029, 2032}},
+		{NewParser(Minute | Hour | Dom | Month | Dow | Year), "0 0 * * * 2090/4", []int{2090, 2094, 2098}},
+		{NewParser(Minute | Hour | Dom | Month | Dow | YearOptional), "0 0 * * *", nil},
+		{NewParser(Minute | Hour | Dom | Month | Dow | YearOptional), "0 0 * * * 2027", []int{2027}},
+		{NewParser(Hour | Dom | Month | Year), "0 * * 2027", []int{2027}},
+	}
+
+	for _, c := range entries {
+		actual, err := c.parser.Parse(c.expr)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", c.expr, err)
+			continue
+		}
+		if years := actual.(*SpecSchedule).Years; !reflect.DeepEqual(years, c.years) {
+			t.Errorf("%s => expected years %v, got %v", c.expr, c.years, years)
+		}
+	}
+
+	parser := NewParser(Minute | Hour | Dom | Month | Dow | Year)
+	var invalid = []struct {
+		parser    Parser
+		expr, err string
+	}{
+		{standardParser, "0 0 * * * 2026", "expected exactly 5 fields"},
+		{parser, "0 0 * * *", "expected exactly 5 fields"},
+		{parser, "0 0 * * * 1969", "below minimum"},
+		{parser, "0 0 * * * 2100", "above maximum"},
+		{parser, "0 0 * * * 2030-2026", "beyond end of range"},
+		{parser, "0 0 * * * 2026/0", "should be a positive number"},
+		{parser, "0 0 * * * next", "failed to parse int from"},
+	}
+	for _, c := range invalid {
+		if _, err := c.parser.Parse(c.expr); err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s => expected %v, got %v", c.expr, c.err, err)
+		}
+	}
+}
+
+func TestYearOptionalIsAnOptional(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic when combining optional fields")
+		}
+	}()
+	NewParser(SecondOptional | Minute | Hour | Dom | Month | Dow | YearOptional)
+}
@@ -0,0 +1,98 @@
+package cron
+
+import (
+	"context"
+	"sync"
+)
+
+// QueueMode 决定当所有工作槽都被占用时如何处理新的运行。
+type QueueMode int
+
+const (
+	// QueueBlock 让新的运行等待空闲的工作槽，排队数量不限。这是默认行为。
+	QueueBlock QueueMode = iota
+	// QueueDrop 立即丢弃新的运行。
+	QueueDrop
+	// QueueLimit 让最多 MaxQueued 个运行等待空闲的工作槽，丢弃超出的运行。
+	QueueLimit
+)
+
+// QueuePolicy 描述 WithMaxConcurrentJobs 的工作池在所有工作槽都被占用时的行为。
+// 等待中的运行在 Cron 停止时被放弃。
+type QueuePolicy struct {
+	// Mode 是工作槽用完时采取的动作。
+	Mode QueueMode
+
+	// MaxQueued 是 QueueLimit 模式下等待工作槽的运行的最大数量。
+	MaxQueued int
+}
+
+// workerPool 是限制同时运行的作业数量的信号量。
+type workerPool struct {
+	slots  chan struct{}
+	policy QueuePolicy
+
+	mu     sync.Mutex
+	queued int
+}
+
+func newWorkerPool(n int, policy QueuePolicy) *workerPool {
+	if n <= 0 {
+		panic("cron: max concurrent jobs must be positive")
+	}
+	return &workerPool{slots: make(chan struct{}, n), policy: policy}
+}
+
+// acquire 获取一个工作槽，必要时按照队列策略等待。
+// 如果运行应该被丢弃，或者在等待期间 ctx 被取消，则返回 false。
+func (p *workerPool) acquire(ctx context.Context) bool {
+	select {
+	case p.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	switch p.policy.Mode {
+	case QueueDrop:
+		return false
+	case QueueLimit:
+		p.mu.Lock()
+		if p.queued >= p.policy.MaxQueued {
+			p.mu.Unlock()
+			return false
+		}
+		p.queued++
+		p.mu.Unlock()
+		defer func() {
+			p.mu.Lock()
+			p.queued--
+			p.mu.Unlock()
+		}()
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// release 释放由 acquire 获取的工作槽。
+func (p *workerPool) release() {
+	<-p.slots
+}
+
+// wrap 返回一个在运行 j 之前获取工作槽的作业。没有获得工作槽的运行被丢弃：
+// 它在Info级别向给定记录器记录丢弃，并返回 ErrSkipped，像被跳过的运行一样报告给监听器。
+func (p *workerPool) wrap(j Job, logger Logger) Job {
+	return FuncErrJob(func(ctx context.Context) error {
+		if !p.acquire(ctx) {
+			info, _ := RunInfoFromContext(ctx)
+			logger.Info("drop", "entry", info.ID)
+			return ErrSkipped
+		}
+		defer p.release()
+		return RunWithContext(ctx, j)
+	})
+}
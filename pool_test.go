@@ -0,0 +1,186 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPoolDrop(t *testing.T) {
+	pool := newWorkerPool(1, QueuePolicy{Mode: QueueDrop})
+	if !pool.acquire(context.Background()) {
+		t.Fatal("expected the first acquire to succeed")
+	}
+	if pool.acquire(context.Background()) {
+		t.Error("expected the second acquire to be dropped")
+	}
+	pool.release()
+	if !pool.acquire(context.Background()) {
+		t.Error("expected acquire to succeed after release")
+	}
+}
+
+func TestWorkerPoolQueueLimit(t *testing.T) {
+	pool := newWorkerPool(1, QueuePolicy{Mode: QueueLimit, MaxQueued: 1})
+	pool.acquire(context.Background())
+
+	queued := make(chan bool)
+	go func() { queued <- pool.acquire(context.Background()) }()
+	for {
+		pool.mu.Lock()
+		n := pool.queued
+		pool.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if pool.acquire(context.Background()) {
+		t.Error("expected acquire beyond the queue limit to be dropped")
+	}
+	pool.release()
+	if !<-queued {
+		t.Error("expected the queued acquire to succeed after release")
+	}
+}
+
+func TestWorkerPoolBlockHonoursContext(t *testing.T) {
+	pool := newWorkerPool(1, QueuePolicy{})
+	pool.acquire(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if pool.acquire(ctx) {
+		t.Error("expected acquire to give up when the context is done")
+	}
+}
+
+func TestMaxConcurrentJobs(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start,
+		WithMaxConcurrentJobs(1, QueuePolicy{Mode: QueueBlock}))
+
+	var running, maxRunning, runs int64
+	job := func() {
+		n := atomic.AddInt64(&running, 1)
+		for {
+			max := atomic.LoadInt64(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt64(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt64(&running, -1)
+		atomic.AddInt64(&runs, 1)
+	}
+	for i := 0; i < 3; i++ {
+		cron.AddFunc("@hourly", job)
+	}
+	cron.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	// 等待中的运行在停止时被放弃，因此先等待所有运行完成。
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt64(&runs) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	<-cron.Stop().Done()
+
+	if runs != 3 {
+		t.Errorf("expected 3 runs, got %d", runs)
+	}
+	if maxRunning != 1 {
+		t.Errorf("expected at most 1 concurrent run, got %d", maxRunning)
+	}
+}
+
+func TestEntryMaxConcurrent(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 30, 0, time.UTC)
+	var listener recordingListener
+	cron, clock := newWithFakeClock(start,
+		WithListener(&listener), WithLogger(DiscardLogger))
+
+	var (
+		once    sync.Once
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	cron.AddFunc("@every 1m", func() {
+		once.Do(func() { close(started) })
+		<-release
+	}, WithEntryMaxConcurrent(1))
+	cron.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	<-started
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	for len(listener.Ends()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	<-cron.Stop().Done()
+
+	ends := listener.Ends()
+	if len(ends) != 2 {
+		t.Fatalf("expected 2 job ends, got %d", len(ends))
+	}
+	if ends[0].Err != ErrSkipped {
+		t.Errorf("expected the overlapping run to be skipped, got %v", ends[0].Err)
+	}
+	if ends[1].Err != nil {
+		t.Errorf("expected the first run to succeed, got %v", ends[1].Err)
+	}
+}
+
+func TestMaxConcurrentJobsInvalid(t *testing.T) {
+	for _, f := range []func(){
+		func() { New(WithMaxConcurrentJobs(0, QueuePolicy{})) },
+		func() { LimitConcurrency(0, DiscardLogger) },
+		func() { WithEntryMaxConcurrent(-1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a non-positive limit to panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+// 测试被工作池丢弃的运行以 ErrSkipped 报告给监听器。
+func TestMaxConcurrentJobsDropNotifiesListener(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	var listener recordingListener
+	cron, clock := newWithFakeClock(start,
+		WithListener(&listener), WithLogger(DiscardLogger),
+		WithMaxConcurrentJobs(1, QueuePolicy{Mode: QueueDrop}))
+
+	release := make(chan struct{})
+	cron.AddFunc("@hourly", func() { <-release })
+	cron.AddFunc("@hourly", func() { <-release })
+	cron.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	for len(listener.Ends()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	<-cron.Stop().Done()
+
+	ends := listener.Ends()
+	if len(ends) != 2 {
+		t.Fatalf("expected 2 job ends, got %d", len(ends))
+	}
+	if ends[0].Err != ErrSkipped {
+		t.Errorf("expected the dropped run to be reported as skipped, got %v", ends[0].Err)
+	}
+}
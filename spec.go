@@ -1,12 +1,23 @@
 package cron
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // SpecSchedule 指定基于传统crontab规范的工作周期（精确到秒）。
 // 它最初被计算并存储为位集。
 type SpecSchedule struct {
 	Second, Minute, Hour, Dom, Month, Dow uint64
 
+	// DomRules 和 DowRules 是月中的日和星期几字段中无法用位集表示的、
+	// 相对于月份的日规则（例如 "L" 和 "5L"）。日期满足对应字段的位集
+	// 或其中任何一条规则时，即视为匹配该字段。
+	DomRules, DowRules []DayRule
+
+	// Years 是调度允许的年份，按升序排列。nil 表示任何年份。
+	Years []int
+
 	// 覆盖此调度的位置。
 	Location *time.Location
 }
@@ -17,6 +28,12 @@ type bounds struct {
 	names    map[string]uint
 }
 
+// 年字段的边界。年份无法放入位集，因此它们不使用 bounds。
+const (
+	minYear = 1970
+	maxYear = 2099
+)
+
 // 每个字段的边界。
 var (
 	seconds = bounds{0, 59, nil}
@@ -83,14 +100,27 @@ func (s *SpecSchedule) Next(t time.Time) time.Time {
 	// 此标志指示字段是否已递增。
 	added := false
 
-	// 如果在五年内找不到时间，则返回零。
+	// 如果在五年内（或者指定了年份时，在最后一个年份之前）找不到时间，则返回零。
 	yearLimit := t.Year() + 5
+	if len(s.Years) > 0 {
+		yearLimit = s.Years[len(s.Years)-1]
+	}
 
 WRAP:
 	if t.Year() > yearLimit {
 		return time.Time{}
 	}
 
+	// 跳到第一个适用的年份。
+	if len(s.Years) > 0 && !s.yearMatches(t.Year()) {
+		year := s.nextYear(t.Year())
+		if year == 0 {
+			return time.Time{}
+		}
+		added = true
+		t = time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	}
+
 	// 找到第一个适用的月份。
 	// 如果是这个月，则什么都不做。
 	for 1<<uint(t.Month())&s.Month == 0 {
@@ -173,12 +203,27 @@ WRAP:
 	return t.In(origLocation)
 }
 
+// yearMatches 如果给定年份是调度允许的年份则返回 true。
+func (s *SpecSchedule) yearMatches(year int) bool {
+	i := sort.SearchInts(s.Years, year)
+	return i < len(s.Years) && s.Years[i] == year
+}
+
+// nextYear 返回晚于给定年份的第一个允许的年份，如果没有则返回 0。
+func (s *SpecSchedule) nextYear(year int) int {
+	i := sort.SearchInts(s.Years, year+1)
+	if i == len(s.Years) {
+		return 0
+	}
+	return s.Years[i]
+}
+
 // dayMatches 如果给定时间满足调度的星期几和月中的日
 // 限制，则返回true。
 func dayMatches(s *SpecSchedule, t time.Time) bool {
 	var (
-		domMatch bool = 1<<uint(t.Day())&s.Dom > 0
-		dowMatch bool = 1<<uint(t.Weekday())&s.Dow > 0
+		domMatch bool = 1<<uint(t.Day())&s.Dom > 0 || matchesAny(s.DomRules, t)
+		dowMatch bool = 1<<uint(t.Weekday())&s.Dow > 0 || matchesAny(s.DowRules, t)
 	)
 	if s.Dom&starBit > 0 || s.Dow&starBit > 0 {
 		return domMatch && dowMatch
@@ -298,3 +298,124 @@ func TestSlash0NoHang(t *testing.T) {
 		t.Error("expected an error on 0 increment")
 	}
 }
+
+func TestNextLastDay(t *testing.T) {
+	parser := NewParser(Second | Minute | Hour | Dom | Month | Dow | LastDay)
+	runs := []struct {
+		time, spec string
+		expected   string
+	}{
+		// 月末，包括二月和闰年。
+		{"Mon Jan 15 00:00 2024", "0 0 0 L * ?", "Wed Jan 31 00:00 2024"},
+		{"Wed Jan 31 00:00 2024", "0 0 0 L * ?", "Thu Feb 29 00:00 2024"},
+		{"Tue Jan 31 00:00 2023", "0 0 0 L * ?", "Tue Feb 28 00:00 2023"},
+		{"Thu Feb 29 00:00 2024", "0 0 0 L * ?", "Sun Mar 31 00:00 2024"},
+		{"Sat Nov 30 12:00 2024", "0 0 9 L * ?", "Tue Dec 31 09:00 2024"},
+
+		// 月末之前的天数。
+		{"Mon Jan 15 00:00 2024", "0 0 0 L-3 * ?", "Sun Jan 28 00:00 2024"},
+		{"Sun Jan 28 00:00 2024", "0 0 0 L-3 * ?", "Mon Feb 26 00:00 2024"},
+		{"Sun Jan 28 00:00 2024", "0 0 0 L-29 * ?", "Sat Mar 2 00:00 2024"},
+
+		// 与普通日期组合。
+		{"Mon Jan 15 00:00 2024", "0 0 0 1,L * ?", "Wed Jan 31 00:00 2024"},
+		{"Wed Jan 31 00:00 2024", "0 0 0 1,L * ?", "Thu Feb 1 00:00 2024"},
+
+		// 月中最后一个星期几。
+		{"Mon Jan 1 00:00 2024", "0 0 0 ? * 5L", "Fri Jan 26 00:00 2024"},
+		{"Fri Jan 26 00:00 2024", "0 0 0 ? * FRIL", "Fri Feb 23 00:00 2024"},
+		{"Fri Feb 23 00:00 2024", "0 0 0 ? * 4L", "Thu Feb 29 00:00 2024"},
+		{"Mon Jan 22 00:00 2024", "0 0 0 ? * 0L,1", "Sun Jan 28 00:00 2024"},
+		{"Sun Jan 28 00:00 2024", "0 0 0 ? * 0L,1", "Mon Jan 29 00:00 2024"},
+	}
+
+	for _, c := range runs {
+		sched, err := parser.Parse(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		actual := sched.Next(getTime(c.time))
+		expected := getTime(c.expected)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.spec, expected, actual)
+		}
+	}
+}
+
+func TestNextWeekdayRules(t *testing.T) {
+	parser := NewParser(Second | Minute | Hour | Dom | Month | Dow | WeekdayRules)
+	runs := []struct {
+		time, spec string
+		expected   string
+	}{
+		// 最近的工作日：2024年6月15日是星期六，9月15日是星期日。
+		{"Sat Jun 1 00:00 2024", "0 0 0 15W * ?", "Fri Jun 14 00:00 2024"},
+		{"Fri Jun 14 00:00 2024", "0 0 0 15W * ?", "Mon Jul 15 00:00 2024"},
+		{"Sun Sep 1 00:00 2024", "0 0 0 15W * ?", "Mon Sep 16 00:00 2024"},
+
+		// 不跨越月份：2024年6月1日是星期六，3月31日是星期日。
+		{"Fri May 31 00:00 2024", "0 0 0 1W * ?", "Mon Jun 3 00:00 2024"},
+		{"Fri Mar 1 00:00 2024", "0 0 0 31W * ?", "Fri Mar 29 00:00 2024"},
+		// 没有31日的月份被跳过。
+		{"Fri Mar 29 00:00 2024", "0 0 0 31W * ?", "Fri May 31 00:00 2024"},
+
+		// 月中最后一个工作日：2024年8月31日是星期六。
+		{"Thu Aug 1 00:00 2024", "0 0 0 LW * ?", "Fri Aug 30 00:00 2024"},
+		{"Fri Aug 30 00:00 2024", "0 0 0 LW * ?", "Mon Sep 30 00:00 2024"},
+
+		// 月中第 n 个星期几。
+		{"Mon Jan 1 00:00 2024", "0 0 0 ? * MON#1", "Mon Feb 5 00:00 2024"},
+		{"Sun Dec 31 00:00 2023", "0 0 0 ? * MON#1", "Mon Jan 1 00:00 2024"},
+		{"Mon Jan 1 00:00 2024", "0 0 0 ? * 2#3", "Tue Jan 16 00:00 2024"},
+		{"Mon Jan 1 00:00 2024", "0 0 0 ? * thu#5", "Thu Feb 29 00:00 2024"},
+		{"Mon Jan 1 00:00 2024", "0 0 0 ? * 1#1,5#5", "Mon Feb 5 00:00 2024"},
+	}
+
+	for _, c := range runs {
+		sched, err := parser.Parse(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		actual := sched.Next(getTime(c.time))
+		expected := getTime(c.expected)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.spec, expected, actual)
+		}
+	}
+}
+
+func TestNextYears(t *testing.T) {
+	parser := NewParser(Second | Minute | Hour | Dom | Month | Dow | Year)
+	runs := []struct {
+		time, spec string
+		expected   string
+	}{
+		{"Thu Jan 1 00:00 2026", "0 0 0 * * ? *", "Fri Jan 2 00:00 2026"},
+		{"Thu Jan 1 00:00 2026", "0 0 12 1 6 ? 2028", "Thu Jun 1 12:00 2028"},
+		// 超过默认的五年限制。
+		{"Thu Jan 1 00:00 2026", "0 0 0 1 1 ? 2040", "Sun Jan 1 00:00 2040"},
+		{"Thu Jan 1 00:00 2026", "0 0 0 29 2 ? 2026-2030", "Tue Feb 29 00:00 2028"},
+		{"Thu Jan 1 00:00 2026", "0 0 0 1 1 ? 2030/5", "Tue Jan 1 00:00 2030"},
+		{"Tue Jan 1 00:00 2030", "0 0 0 1 1 ? 2030/5", "Mon Jan 1 00:00 2035"},
+		{"Sat Dec 31 23:59:59 2033", "0 0 0 1 1 ? 2028,2034", "Sun Jan 1 00:00 2034"},
+
+		// 最后一个年份之后没有激活。
+		{"Tue Jan 1 00:00 2030", "0 0 0 * * ? 2026-2029", ""},
+		{"Thu Jan 1 00:00 2026", "0 0 0 30 2 ? 2026-2099", ""},
+	}
+
+	for _, c := range runs {
+		sched, err := parser.Parse(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		actual := sched.Next(getTime(c.time))
+		expected := getTime(c.expected)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.spec, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EntryState 是 JobStore 为命名条目保存的运行状态。
+type EntryState struct {
+	// Name 是条目的稳定名称，用作存储的键。
+	Name string `json:"name"`
+	// Prev 是条目上次运行的激活时间。
+	Prev time.Time `json:"prev"`
+	// Next 是保存状态时条目的下一次激活时间。
+	Next time.Time `json:"next"`
+	// RunCount 是条目按计划运行的次数。
+	RunCount int `json:"runCount,omitempty"`
+}
+
+// JobStore 持久化命名条目的运行状态，使 Prev 和 Next 能够在进程重启后保留。
+//
+// 只有使用 WithName 设置了名称的条目才会被保存。Cron 在计算条目的首次激活时间时
+// 加载它的状态，并在每次激活之后在单独的 goroutine 中保存它。
+type JobStore interface {
+	// Load 返回给定名称条目保存的状态。如果没有保存的状态，ok 为 false。
+	Load(name string) (state EntryState, ok bool, err error)
+	// Save 保存条目的状态，覆盖该名称之前的状态。
+	Save(state EntryState) error
+}
+
+// MemoryJobStore 是在内存中保存条目状态的 JobStore。
+// 它不能在进程重启后保留状态，但可以在同一进程中的多个 Cron 实例之间共享。
+type MemoryJobStore struct {
+	mu     sync.Mutex
+	states map[string]EntryState
+}
+
+// NewMemoryJobStore 返回一个空的 MemoryJobStore。
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{states: make(map[string]EntryState)}
+}
+
+// Load 实现 JobStore。
+func (s *MemoryJobStore) Load(name string) (EntryState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[name]
+	return state, ok, nil
+}
+
+// Save 实现 JobStore。
+func (s *MemoryJobStore) Save(state EntryState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.Name] = state
+	return nil
+}
+
+// FileJobStore 是将条目状态以 JSON 格式保存在本地文件中的 JobStore。
+// 每次保存都会先写入临时文件再重命名，因此文件总是完整的。
+type FileJobStore struct {
+	mu     sync.Mutex
+	path   string
+	states map[string]EntryState
+}
+
+// NewFileJobStore 返回一个使用给定路径的 FileJobStore。
+// 如果文件已经存在，则加载其中的状态；如果不存在，则在第一次保存时创建它。
+func NewFileJobStore(path string) (*FileJobStore, error) {
+	s := &FileJobStore{path: path, states: make(map[string]EntryState)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var states []EntryState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to parse job store %s: %v", path, err)
+	}
+	for _, state := range states {
+		s.states[state.Name] = state
+	}
+	return s, nil
+}
+
+// Load 实现 JobStore。
+func (s *FileJobStore) Load(name string) (EntryState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[name]
+	return state, ok, nil
+}
+
+// Save 实现 JobStore。
+func (s *FileJobStore) Save(state EntryState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, existed := s.states[state.Name]
+	s.states[state.Name] = state
+	if err := s.flush(); err != nil {
+		// 保持内存中的状态与文件一致。
+		if existed {
+			s.states[state.Name] = prev
+		} else {
+			delete(s.states, state.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+// flush 将所有状态原子地写入文件。
+func (s *FileJobStore) flush() error {
+	states := make([]EntryState, 0, len(s.states))
+	for _, state := range s.states {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// restoreEntry 计算条目的首次激活时间。如果配置了 JobStore 并且保存了
+// 条目上次运行的时间，则从该时间继续计划：这样 @every 条目在重启后保持它的间隔，
+// 而重启期间错过的激活会立即到期，并按照条目的 misfire 策略处理。
+func (c *Cron) restoreEntry(e *Entry, now time.Time) {
+	e.Next = e.next(now)
+	if c.store == nil || e.Name == "" {
+		return
+	}
+	// 尚未写入存储的状态比存储中的更新。
+	c.savesMu.Lock()
+	state, ok := c.saves[e.Name]
+	c.savesMu.Unlock()
+	if !ok {
+		var err error
+		state, ok, err = c.store.Load(e.Name)
+		if err != nil {
+			c.logger.Error(err, "failed to load entry state", "entry", e.ID, "name", e.Name)
+			return
+		}
+	}
+	if !ok {
+		return
+	}
+	e.Prev = state.Prev
+	e.RunCount = state.RunCount
+	if e.Prev.IsZero() {
+		e.Next = e.next(now)
+		return
+	}
+	fromPrev := e.next(e.Prev)
+	e.Next = e.next(now)
+	if !fromPrev.IsZero() && (e.Next.IsZero() || fromPrev.Before(e.Next)) {
+		// 有状态的调度（例如 JitterSchedule）记住最后一次计算的激活，
+		// 因此最后计算被选中的那一个。
+		e.Next = e.next(e.Prev)
+	}
+	c.logger.Info("restored", "entry", e.ID, "name", e.Name, "prev", e.Prev, "next", e.Next)
+}
+
+// saveEntry 如果配置了 JobStore，则排队保存命名条目的当前状态。
+// 状态由 saveStates 在调度循环之外写入，因此缓慢的存储不会延迟激活；
+// 同一条目尚未写入的状态被新的状态替换。
+// 跟随者不保存状态，以免覆盖领导者保存在共享存储中的状态。
+func (c *Cron) saveEntry(e *Entry) {
+	if c.store == nil || e.Name == "" || !c.isLeader() {
+		return
+	}
+	c.savesMu.Lock()
+	c.saves[e.Name] = EntryState{Name: e.Name, Prev: e.Prev, Next: e.Next, RunCount: e.RunCount}
+	c.savesMu.Unlock()
+	select {
+	case c.saveWake <- struct{}{}:
+	default:
+	}
+}
+
+// saveStates 在自己的 goroutine 中将排队的状态写入 JobStore，直到 ctx 结束，
+// 并在结束之前写入剩余的状态。Stop 返回的上下文会等待它结束。
+func (c *Cron) saveStates(ctx context.Context) {
+	c.jobWaiter.Add(1)
+	go func() {
+		defer c.jobWaiter.Done()
+		for {
+			select {
+			case <-c.saveWake:
+				c.flushStates()
+			case <-ctx.Done():
+				c.flushStates()
+				return
+			}
+		}
+	}()
+}
+
+// flushStates 将排队的状态写入 JobStore。状态在写入之后才从队列中删除，
+// 使 restoreEntry 总能看到最新的状态；flushMu 保证重新启动后的写入不会被之前的写入覆盖。
+func (c *Cron) flushStates() {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.savesMu.Lock()
+	states := make([]EntryState, 0, len(c.saves))
+	for _, state := range c.saves {
+		states = append(states, state)
+	}
+	c.savesMu.Unlock()
+
+	for _, state := range states {
+		if err := c.store.Save(state); err != nil {
+			c.logger.Error(err, "failed to save entry state", "name", state.Name)
+		}
+		c.savesMu.Lock()
+		if c.saves[state.Name] == state {
+			delete(c.saves, state.Name)
+		}
+		c.savesMu.Unlock()
+	}
+}
@@ -0,0 +1,158 @@
+package cron
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryJobStore(t *testing.T) {
+	store := NewMemoryJobStore()
+	if _, ok, err := store.Load("backup"); ok || err != nil {
+		t.Fatalf("expected no state, got ok=%v err=%v", ok, err)
+	}
+
+	state := EntryState{
+		Name: "backup",
+		Prev: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Next: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	if err := store.Save(state); err != nil {
+		t.Fatal(err)
+	}
+	loaded, ok, err := store.Load("backup")
+	if !ok || err != nil || loaded != state {
+		t.Errorf("expected %+v, got %+v (ok=%v err=%v)", state, loaded, ok, err)
+	}
+}
+
+func TestFileJobStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cron.json")
+	store, err := NewFileJobStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	states := []EntryState{
+		{Name: "a", Prev: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "b", Next: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, state := range states {
+		if err := store.Save(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 新的存储应该从文件中加载相同的状态。
+	reopened, err := NewFileJobStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range states {
+		loaded, ok, err := reopened.Load(state.Name)
+		if !ok || err != nil || !loaded.Prev.Equal(state.Prev) || !loaded.Next.Equal(state.Next) {
+			t.Errorf("expected %+v, got %+v (ok=%v err=%v)", state, loaded, ok, err)
+		}
+	}
+}
+
+func TestFileJobStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cron.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileJobStore(path); err == nil {
+		t.Error("expected an error for an invalid store file")
+	}
+}
+
+// 测试 @every 条目在重启后保持它的间隔。
+func TestJobStoreRestoresInterval(t *testing.T) {
+	store := NewMemoryJobStore()
+	prev := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	store.Save(EntryState{Name: "sync", Prev: prev})
+
+	cron, _ := newWithFakeClock(prev.Add(20*time.Minute), WithJobStore(store))
+	cron.AddFunc("@every 1h", func() {}, WithName("sync"))
+	cron.Start()
+	defer cron.Stop()
+
+	entry := cron.Entries()[0]
+	if !entry.Prev.Equal(prev) {
+		t.Errorf("expected prev %v, got %v", prev, entry.Prev)
+	}
+	if expected := prev.Add(time.Hour); !entry.Next.Equal(expected) {
+		t.Errorf("expected next %v, got %v", expected, entry.Next)
+	}
+}
+
+// 测试重启期间错过的激活会在启动时运行，并且运行会被记录。
+func TestJobStoreRunsMissedActivation(t *testing.T) {
+	store := NewMemoryJobStore()
+	prev := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.Save(EntryState{Name: "report", Prev: prev})
+
+	now := prev.Add(36 * time.Hour)
+	cron, clock := newWithFakeClock(now, WithJobStore(store))
+	ran := make(chan struct{}, 1)
+	cron.AddFunc("@daily", func() { ran <- struct{}{} }, WithName("report"))
+	cron.AddFunc("@daily", func() { t.Error("expected unnamed entry does not run") })
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-ran:
+	case <-time.After(OneSecond):
+		t.Fatal("expected missed activation to run on start")
+	}
+
+	// 状态在调度循环之外写入，Stop 等待它们写完。
+	clock.BlockUntil(1)
+	<-cron.Stop().Done()
+	state, _, _ := store.Load("report")
+	if expected := prev.Add(24 * time.Hour); !state.Prev.Equal(expected) {
+		t.Errorf("expected recorded prev %v, got %v", expected, state.Prev)
+	}
+	if expected := prev.Add(48 * time.Hour); !state.Next.Equal(expected) {
+		t.Errorf("expected recorded next %v, got %v", expected, state.Next)
+	}
+}
+
+// blockingJobStore 是在 release 关闭之前阻塞 Save 的 JobStore。
+type blockingJobStore struct {
+	*MemoryJobStore
+	release chan struct{}
+}
+
+func (s blockingJobStore) Save(state EntryState) error {
+	<-s.release
+	return s.MemoryJobStore.Save(state)
+}
+
+// 测试缓慢的存储不会延迟激活，并且 Stop 等待最新的状态被写入。
+func TestJobStoreSavesOffRunLoop(t *testing.T) {
+	store := blockingJobStore{NewMemoryJobStore(), make(chan struct{})}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cron, clock := newWithFakeClock(start, WithJobStore(store))
+	ran := make(chan struct{}, 2)
+	cron.AddFunc("@every 1m", func() { ran <- struct{}{} }, WithName("poll"))
+	cron.Start()
+
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+		select {
+		case <-ran:
+		case <-time.After(OneSecond):
+			t.Fatalf("activation %d was blocked by the store", i+1)
+		}
+	}
+
+	close(store.release)
+	<-cron.Stop().Done()
+	state, _, _ := store.Load("poll")
+	if state.RunCount != 2 || !state.Prev.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("expected the latest state to be saved, got %+v", state)
+	}
+}